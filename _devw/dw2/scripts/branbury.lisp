@@ -0,0 +1,35 @@
+; branbury.lisp
+;
+; dta5 development world scripts (see dta5/scripts/lisp)
+;
+; updated 2026-10-19
+;
+
+(define (times-touched x)
+  (let ((n (data x "touched")))
+    (if (number? n) n 0)))
+
+; The engraved band in the underground chamber is cold when first picked up,
+; and warms after a little while.
+;
+(script "band_chill"
+  (set-data! obj "touched" (+ 1 (times-touched obj)))
+  (when (= (times-touched obj) 1)
+    (tell subj "The band is icy cold to the touch.")
+    (schedule 5 (lambda ()
+      (tell subj (concat (the obj) " has warmed in your hand.")))))
+  #t)
+
+; The underground chamber echoes anything shouted in it.
+;
+(trigger "chamber_echo"
+  (when (contains? text "!")
+    (schedule 1 (lambda ()
+      (announce place (concat "An echo rings back: \"" text "\""))))))
+
+; Picking up (and then wearing) the band advances the "iron_band" quest.
+;
+(script "band_found"
+  (when (eq? (loc obj) subj)
+    (quest-start subj "iron_band")
+    (quest-achieve subj "iron_band" (if (eq? verb "wear") "worn" "found"))))
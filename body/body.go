@@ -4,7 +4,7 @@
 // Bodies are for Things (like PlayerChars) that can wear clothes and
 // participate in combat.
 //
-// updated 2017-08-30
+// updated 2026-10-19
 //
 package body
 
@@ -40,6 +40,9 @@ type Interface interface {
   HeldSlotName(string) string
   SetHeld(string, thing.Thing)
   IsHolding(thing.Thing) bool
+  CarryMass() float32
+  CarryBulk() float32
+  WornBulk() float32
 }
 
 type Bodied interface {
@@ -93,6 +96,72 @@ var BasicHeldParts = map[string]string {
 type BasicBody struct {
   wearSlots map[string] byte
   holdSlots map[string] thing.Thing
+  kind      *Type
+}
+
+// A Type is a kind of body, as far as how much it can carry goes:
+//  * Mass is how much mass it can carry, all told (before anything like
+//    strength is taken into account)
+//  * Bulk is how much bulk it can carry, all told
+//  * Worn is how much of that bulk can be worn (rather than held)
+// Body Types are declared in world files (see dta5/load):
+//
+//  ["body", "tag", mass, bulk, worn ]
+//
+type Type struct {
+  Tag  string
+  Mass float32
+  Bulk float32
+  Worn float32
+}
+
+// The Type of body characters have unless they have some other.
+//
+var DefaultType string = "humanoid"
+
+var defaultTypes = []Type{
+  Type{ Tag: "humanoid", Mass: 25.0, Bulk: 250.0, Worn: 30.0, },
+}
+
+var Types map[string]*Type
+
+// This function prepares the package for loading the game (declaring the
+// built-in body Types, which world files may redeclare). This should be
+// called both on initial game loading and when loading a saved game state.
+//
+func Initialize() {
+  Types = make(map[string]*Type)
+  for _, t := range defaultTypes {
+    NewType(t.Tag, t.Mass, t.Bulk, t.Worn)
+  }
+}
+
+func init() {
+  Initialize()
+}
+
+// NewType() declares a body Type (replacing any with the same tag).
+//
+func NewType(tag string, mass, bulk, worn float32) *Type {
+  t := &Type{ Tag: tag, Mass: mass, Bulk: bulk, Worn: worn, }
+  Types[tag] = t
+  return t
+}
+
+// typeOf() returns the Type with the given tag, or the DefaultType.
+//
+func typeOf(tag string) *Type {
+  if t, ok := Types[tag]; ok {
+    return t
+  }
+  if tag != "" {
+    log(dtalog.WRN, "no body type %q; using %q", tag, DefaultType)
+  }
+  if t, ok := Types[DefaultType]; ok {
+    return t
+  }
+  log(dtalog.ERR, "no default body type %q", DefaultType)
+  return &defaultTypes[0]
 }
 
 // WornSlots() returns the number of items that can be worn on the body part
@@ -165,9 +234,43 @@ func (bb BasicBody) IsHolding(t thing.Thing) bool {
   return false
 }
 
+// Return the body's Type.
+//
+func (bb BasicBody) Type() *Type {
+  return bb.kind
+}
+
+// Return how much mass the body can carry, in its hands and on it, all
+// together.
+//
+func (bb BasicBody) CarryMass() float32 {
+  return bb.kind.Mass
+}
+
+// Return how much bulk the body can carry, in its hands and on it, all
+// together.
+//
+func (bb BasicBody) CarryBulk() float32 {
+  return bb.kind.Bulk
+}
+
+// Return how much bulk the body can wear.
+//
+func (bb BasicBody) WornBulk() float32 {
+  return bb.kind.Worn
+}
+
 // Create and return a *BasicBody with all the appropriate slots for a humanoid
-// creature.
+// creature, of the DefaultType.
 func NewHumaniod() *BasicBody {
+  return New("")
+}
+
+// Create and return a *BasicBody with all the appropriate slots for a humanoid
+// creature, of the Type with the given tag (or the DefaultType, if there's
+// no such Type).
+//
+func New(tag string) *BasicBody {
   nb := BasicBody{
     wearSlots: map[string]byte{
       "head": 1,
@@ -188,6 +291,7 @@ func NewHumaniod() *BasicBody {
       "right_hand": nil,
       "left_hand": nil,
     },
+    kind: typeOf(tag),
   }
   return &nb
 }
@@ -2,7 +2,7 @@
 //
 // dta5 managing pages of disk-borne descriptions
 //
-// updated 2017-08-30
+// updated 2026-10-19
 //
 // DTA5 deals with a great deal of text; it's the only information that
 // players see, and it's used to build the entirety of a fictional world
@@ -84,6 +84,12 @@ var Paths []string
 //
 var Limbo map[string]*string
 
+// The page of every described ref, whether or not it's loaded, so things
+// destroyed and made again (as when a dta5/zone is reset) can get their
+// descriptions back; see Restore().
+//
+var pageOf = make(map[string]*string)
+
 // Initialize() iterates through the files in the supplied directory, reading
 // each one in turn and setting each desc.Interface-implementing item's
 // description pointer to the appropriate page (or putting it in Limbo).
@@ -108,6 +114,7 @@ func Initialize(basePath string) error {
   
   Paths = make([]string, 0, len(filez))
   Limbo = make(map[string]*string)
+  pageOf = make(map[string]*string)
   
   for _, fname := range filez {
     pth := filepath.Join(basePath, fname)
@@ -142,6 +149,7 @@ func Initialize(basePath string) error {
       }
       
       idx = raw_slice[0].(string)
+      pageOf[idx] = cur_ptr
       i = ref.Deref(idx)
       if i == nil {
         Limbo[idx] = cur_ptr
@@ -163,6 +171,15 @@ func UnLimbo(x Interface) {
   }
 }
 
+// Restore() sets the description page pointer of an object that has been
+// made again after being destroyed.
+//
+func Restore(x Interface) {
+  if sptr, ok := pageOf[x.(ref.Interface).Ref()]; ok {
+    x.SetDescPage(sptr)
+  }
+}
+
 // Load the page whose descriptions are in the given file.
 //
 func LoadPage(pth string) error {
@@ -2,7 +2,7 @@
 //
 // dta5 doors and doorways
 //
-// updated 2017-08-30
+// updated 2026-10-19
 //
 // Doors represent portals between room.Rooms. Rooms can be connected
 // directly via the cardinal directions, but if a richer passage between
@@ -18,7 +18,7 @@
 package door
 
 import( "fmt";
-        "dta5/log"; "dta5/ref"; "dta5/thing"; "dta5/save"
+        "dta5/log"; "dta5/ref"; "dta5/room"; "dta5/thing"; "dta5/save"
 )
 
 func log(lvl dtalog.LogLvl, fmtstr string, args ...interface{}) {
@@ -35,14 +35,17 @@ type Doorway struct {
   thing.Item
   WillToggle bool
   binder *Door
+  lock   *thing.Lock  // only until the Doorway is bound into a Door
 }
 
-// A Door connects two Doorways together.
+// A Door connects two Doorways together. Its Lock (if it has one) is shared
+// by both Doorways.
 //
 type Door struct {
   Side0  *Doorway
   Side1  *Doorway
   IsOpen bool
+  Lock   *thing.Lock
 }
 
 // Door objects never need to be placed anywhere in the game world; they don't
@@ -83,6 +86,12 @@ func Bind(dwy0, dwy1 *Doorway, isOpen bool) {
   }
   
   nd := Door{ Side0: dwy0, Side1: dwy1, IsOpen: isOpen, }
+  if dwy0.lock != nil {
+    nd.Lock = dwy0.lock
+  } else {
+    nd.Lock = dwy1.lock
+  }
+  dwy0.lock, dwy1.lock = nil, nil
   Doors = append(Doors, &nd)
   dwy0.binder = &nd
   dwy1.binder = &nd
@@ -101,6 +110,30 @@ func (dwyp *Doorway) SetOpen(isOpen bool) {
   dwyp.binder.IsOpen = isOpen
 }
 
+// *Doorway implements thing.Lockable; the Lock belongs to its Door. (A
+// Doorway can be given a Lock before it's bound, and its Door will get
+// that Lock.)
+//
+func (dwy Doorway) Lock() *thing.Lock {
+  if dwy.binder == nil {
+    return dwy.lock
+  }
+  return dwy.binder.Lock
+}
+func (dwyp *Doorway) SetLock(l *thing.Lock) {
+  if dwyp.binder == nil {
+    dwyp.lock = l
+  } else {
+    dwyp.binder.Lock = l
+  }
+}
+
+// IsBound() returns whether the Doorway has been bound into a Door.
+//
+func (dwy Doorway) IsBound() bool {
+  return dwy.binder != nil
+}
+
 // Other() returns a pointer to the other half of the Doorway's Door.
 //
 func (dwyp *Doorway) Other() *Doorway {
@@ -111,6 +144,24 @@ func (dwyp *Doorway) Other() *Doorway {
   }
 }
 
+// LeadsTo() returns the room.Room one ends up in by going through the
+// Doorway (the one the other half is in, or is in something in), or nil.
+// (*Doorway implements room.Passage.)
+//
+func (dwyp *Doorway) LeadsTo() *room.Room {
+  if dwyp.binder == nil {
+    return nil
+  }
+  switch p := dwyp.Other().Loc().Place.(type) {
+  case *room.Room:
+    return p
+  case thing.Thing:
+    r, _ := p.Loc().Place.(*room.Room)
+    return r
+  }
+  return nil
+}
+
 func (dwy Doorway) Save(s save.Saver) {
   var cont []interface{} = make([]interface{}, 0, 8)
   cont = append(cont, "dwy")
@@ -131,4 +182,7 @@ func (d Door) Save(s save.Saver) {
   cont = append(cont, d.Side1.Ref())
   cont = append(cont, d.IsOpen)
   s.Encode(cont)
+  if d.Lock != nil {
+    d.Lock.Save(s, d.Side0.Ref())
+  }
 }
@@ -0,0 +1,46 @@
+// door_test.go
+//
+// Test suite for dta5/door
+//
+package door
+
+import( "testing";
+        "dta5/thing";
+)
+
+func TestSharedLock(t *testing.T) {
+  Reset()
+  key := thing.NewItem("dt-key", "a brass key", "", false, 0.01, 0.01)
+  other := thing.NewItem("dt-other", "a tin key", "", false, 0.01, 0.01)
+
+  tests := []struct {
+    desc  string
+    setup func(a, b *Doorway)
+  }{
+    { "locked before binding", func(a, b *Doorway) {
+        b.SetLock(&thing.Lock{ Locked: true, Keys: []string{ "dt-key", }, })
+        Bind(a, b, false)
+      }, },
+    { "locked after binding", func(a, b *Doorway) {
+        Bind(a, b, false)
+        a.SetLock(&thing.Lock{ Locked: true, Keys: []string{ "dt-key", }, })
+      }, },
+  }
+  for _, tc := range tests {
+    a := New("dt-a", "a door", "", "x", "x", true)
+    b := New("dt-b", "a door", "", "x", "x", true)
+    tc.setup(a, b)
+
+    if a.Lock() == nil || a.Lock() != b.Lock() {
+      t.Errorf("%s: sides have locks %p and %p, want the same one", tc.desc, a.Lock(), b.Lock())
+      continue
+    }
+    if !a.Lock().Fits(key) || (a.Lock().KeyFor(other) != nil) {
+      t.Errorf("%s: the wrong keys fit", tc.desc)
+    }
+    b.Lock().Locked = false
+    if a.Lock().Locked {
+      t.Errorf("%s: unlocking one side left the other locked", tc.desc)
+    }
+  }
+}
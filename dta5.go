@@ -3,7 +3,7 @@
 // The FIFTH try at a text adventure. The... thee-and-a-halfth? Try at
 // an online multi-player environment.
 //
-// updated 2017-08-28
+// updated 2026-10-19
 //
 // At any given time, this is not guaranteed to run on any platform other
 // than the one I'm using as a game server.
@@ -37,9 +37,11 @@ import( "bufio"; "bytes"; "fmt"; "flag"; "net"; "os"; "path/filepath";
         "strings"; "time";
         "github.com/d2718/dconfig";
         "dta5/log";
-        "dta5/act"; "dta5/desc"; "dta5/door"; "dta5/load"; "dta5/mood";
-        "dta5/msg"; "dta5/pc"; "dta5/ref"; "dta5/room"; "dta5/scripts";
-        "dta5/scripts/more"; "dta5/save";
+        "dta5/act"; "dta5/body"; "dta5/desc"; "dta5/door"; "dta5/factory"; "dta5/load"; "dta5/mood";
+        "dta5/msg"; "dta5/pc"; "dta5/quest"; "dta5/ref"; "dta5/room";
+        "dta5/scripts";
+        "dta5/scripts/lisp"; "dta5/scripts/more"; "dta5/save"; "dta5/shop";
+        "dta5/stats"; "dta5/thing"; "dta5/vehicle"; "dta5/zone";
 )
 
 const DEBUG = false
@@ -50,6 +52,9 @@ const mainWorldFile string = "main.json"
 // Name of the directory in the world directory where the dta5/desc
 // description files live.
 const descPath string      = "descs"
+// Name of the directory in the world directory where dta5/scripts/lisp
+// script files live.
+const scriptPath string    = "scripts"
 // Name of the control socket through which commands are issued to the server.
 const sockName string      = "ctrl"
 
@@ -229,6 +234,7 @@ func processCommand(cmd string) {
       d.Save(*s)
     }
     ref.SaveData(*s)
+    factory.Save(*s)
     scripts.SaveBindings(*s)
     
     log(dtalog.DBG, "processCommand(): save complete")
@@ -244,7 +250,18 @@ func processCommand(cmd string) {
     load_path := filepath.Join(worldDir, "saves", rest + ".json")
     ref.Reset()
     door.Reset()
+    scripts.Initialize()
+    factory.Initialize()
+    load.Reset()
+    room.Initialize()
+    zone.Initialize()
+    vehicle.Initialize()
+    lisp.LoadDir(filepath.Join(worldDir, scriptPath))
     mood.Initialize()
+    quest.Initialize()
+    shop.Initialize()
+    stats.Initialize()
+    body.Initialize()
     load.LoadFile(filepath.Join(worldDir, mainWorldFile), load.PERM)
     load.LoadFile(load_path, load.MUT)
     desc.Initialize(filepath.Join(worldDir, descPath))
@@ -257,9 +274,31 @@ func processCommand(cmd string) {
     for _, mp := range mood.Messengers {
       mp.Arm()
     }
+    for _, sp := range shop.Shops {
+      sp.Arm()
+    }
+    for _, sp := range factory.Spawners {
+      sp.Arm()
+    }
+    for _, z := range zone.Zones {
+      z.Arm()
+    }
+    for _, v := range vehicle.Vehicles {
+      v.Arm()
+    }
+    scripts.ArmTimers()
+    thing.ArmLights()
     
     log(dtalog.DBG, "processCommand(): load complete")
   
+  case "reset":
+    z, ok := zone.Zones[rest]
+    if !ok {
+      log(dtalog.MSG, "processCommand(): there is no zone %q to reset.", rest)
+      return
+    }
+    z.Reset()
+  
   case "logout":
     lo_slice := strings.SplitN(rest, " ", 2)
     if len(lo_slice) < 2 {
@@ -307,7 +346,18 @@ func main() {
   pc.PlayerDir = filepath.Join(worldDir, "pc_dir")
   
   more.Initialize()
+  scripts.Initialize()
+  factory.Initialize()
+  load.Reset()
+  room.Initialize()
+  zone.Initialize()
+  vehicle.Initialize()
+  lisp.LoadDir(filepath.Join(worldDir, scriptPath))
   mood.Initialize()
+  quest.Initialize()
+  shop.Initialize()
+  stats.Initialize()
+  body.Initialize()
   load.LoadFile(filepath.Join(worldDir, mainWorldFile), load.INIT)
   desc.Initialize(filepath.Join(worldDir, descPath))
   act.Initialize(actionQueueLength)
@@ -319,6 +369,20 @@ func main() {
   for _, mp := range mood.Messengers {
     mp.Arm()
   }
+  for _, sp := range shop.Shops {
+    sp.Arm()
+  }
+  for _, sp := range factory.Spawners {
+    sp.Arm()
+  }
+  for _, z := range zone.Zones {
+    z.Arm()
+  }
+  for _, v := range vehicle.Vehicles {
+    v.Arm()
+  }
+  scripts.ArmTimers()
+  thing.ArmLights()
   
   go listenForConnections()
   // go listenToStdin()
@@ -0,0 +1,212 @@
+// effect.go
+//
+// dta5 effects of consuming (and otherwise using) things
+//
+// updated 2026-10-19
+//
+// An Effect (see thing.Effect) is something that happens to a character,
+// named by a tag and given some arguments; which Effects a thing.Consumable
+// has is specified in the world file. What each tag does is up to the Func
+// registered for it in Effects, so more can be added (from anywhere that
+// imports this package) just like Scripts (see dta5/scripts). These are
+// built in:
+//
+//  [ "heal", n ]                    restores n points of health (see
+//                                   dta5/stats); negative n does damage
+//  [ "message", "text" ]            tells the character something
+//  [ "status", "name", secs (, "start text" (, "end text")) ]
+//                                   gives the character a temporary status
+//                                   ("well-fed", "invisible") for secs
+//                                   seconds
+//
+// Statuses aren't saved; they wear off, at the latest, when the character
+// leaves the game.
+//
+package effect
+
+import( "fmt"; "sort"; "time";
+        "dta5/act"; "dta5/log"; "dta5/msg"; "dta5/ref"; "dta5/stats";
+        "dta5/thing";
+)
+
+func log(lvl dtalog.LogLvl, fmtstr string, args ...interface{}) {
+  dtalog.Log(lvl, fmt.Sprintf("effect: " + fmtstr, args...))
+}
+
+// Anything with stats that's in the game can be affected. pc.PlayerChar is
+// the only Target.
+//
+type Target interface {
+  stats.Holder
+  ref.Interface
+}
+
+// A Func makes an Effect happen to who. The src is the thing that caused
+// it (if any; scripts might cause Effects from nowhere in particular).
+//
+type Func func(who Target, src thing.Thing, args []interface{}) error
+
+// Effects associates each Func with its tag.
+//
+var Effects = map[string]Func {
+  "heal":    heal,
+  "message": message,
+  "status":  status,
+}
+
+// Apply() makes each of effs happen to who, in order. Unknown tags and
+// Funcs that fail are logged (and skipped).
+//
+func Apply(who Target, src thing.Thing, effs []thing.Effect) {
+  for _, e := range effs {
+    f, ok := Effects[e.Tag]
+    if !ok {
+      log(dtalog.WRN, "Apply(%q): no effect %q", who.Ref(), e.Tag)
+      continue
+    }
+    if err := f(who, src, e.Args); err != nil {
+      log(dtalog.WRN, "Apply(%q): %q: %s", who.Ref(), e.Tag, err)
+    }
+  }
+}
+
+func argNum(args []interface{}, n int) (float64, error) {
+  if n >= len(args) {
+    return 0, fmt.Errorf("missing argument %d", n + 1)
+  }
+  if f, ok := args[n].(float64); ok {
+    return f, nil
+  }
+  return 0, fmt.Errorf("argument %d (%v) is not a number", n + 1, args[n])
+}
+
+func argStr(args []interface{}, n int) (string, error) {
+  if n >= len(args) {
+    return "", fmt.Errorf("missing argument %d", n + 1)
+  }
+  if s, ok := args[n].(string); ok {
+    return s, nil
+  }
+  return "", fmt.Errorf("argument %d (%v) is not a string", n + 1, args[n])
+}
+
+func tell(who Target, text string) {
+  who.Deliver(msg.New("txt", "%s", text))
+}
+
+func heal(who Target, src thing.Thing, args []interface{}) error {
+  n, err := argNum(args, 0)
+  if err != nil {
+    return err
+  }
+  switch d := who.StatSheet().Heal(int(n)); {
+  case d > 0:
+    tell(who, "You feel better.")
+  case d < 0:
+    tell(who, "You feel worse.")
+  }
+  return nil
+}
+
+func message(who Target, src thing.Thing, args []interface{}) error {
+  text, err := argStr(args, 0)
+  if err != nil {
+    return err
+  }
+  tell(who, text)
+  return nil
+}
+
+func status(who Target, src thing.Thing, args []interface{}) error {
+  name, err := argStr(args, 0)
+  if err != nil {
+    return err
+  }
+  secs, err := argNum(args, 1)
+  if err != nil {
+    return err
+  }
+  var start_text, end_text string
+  if len(args) > 2 {
+    if start_text, err = argStr(args, 2); err != nil {
+      return err
+    }
+  }
+  if len(args) > 3 {
+    if end_text, err = argStr(args, 3); err != nil {
+      return err
+    }
+  }
+  AddStatus(who, name, secs, end_text)
+  if start_text != "" {
+    tell(who, start_text)
+  }
+  return nil
+}
+
+// A Status is a temporary condition. Giving someone a Status they already
+// have just changes when it wears off.
+//
+type Status struct {
+  Name  string
+  Until time.Time
+  gen   int
+}
+
+// Everyone's current Statuses, by their refs and then by name.
+//
+var active = make(map[string]map[string]*Status)
+
+// AddStatus() gives who the named Status for secs seconds; when it wears
+// off, they're told endText (unless it's "").
+//
+func AddStatus(who Target, name string, secs float64, endText string) {
+  sts, ok := active[who.Ref()]
+  if !ok {
+    sts = make(map[string]*Status)
+    active[who.Ref()] = sts
+  }
+  st, ok := sts[name]
+  if !ok {
+    st = &Status{ Name: name, }
+    sts[name] = st
+  }
+  st.gen++
+  st.Until = time.Now().Add(time.Duration(secs * float64(time.Second)))
+
+  gen := st.gen
+  act.Add(secs, func() error {
+    if (active[who.Ref()] == nil) || (active[who.Ref()][name] != st) || (st.gen != gen) {
+      return nil
+    }
+    delete(active[who.Ref()], name)
+    if (endText != "") && (ref.Deref(who.Ref()) == ref.Interface(who)) {
+      tell(who, endText)
+    }
+    return nil
+  })
+}
+
+// Has() returns whether who currently has the named Status.
+//
+func Has(who Target, name string) bool {
+  _, ok := active[who.Ref()][name]
+  return ok
+}
+
+// Statuses() returns the names of who's current Statuses, sorted.
+//
+func Statuses(who Target) []string {
+  names := make([]string, 0, len(active[who.Ref()]))
+  for n := range active[who.Ref()] {
+    names = append(names, n)
+  }
+  sort.Strings(names)
+  return names
+}
+
+// Clear() takes away all of who's Statuses (without telling them).
+//
+func Clear(who Target) {
+  delete(active, who.Ref())
+}
@@ -0,0 +1,289 @@
+// factory.go
+//
+// dta5 making and destroying things during play
+//
+// updated 2026-10-19
+//
+// Everything in the world file has a reference string picked by whoever
+// wrote it, but things made during play need references nobody's using.
+// NewRef() makes them from a prefix and a counter; the counters are saved
+// with the game, so a reference isn't reused even after the thing it
+// belonged to is gone:
+//
+//  ["serial", "prefix", next_number ]
+//
+// Spawn() makes a copy of a prototype (see thing.Clone()) with a new
+// reference, and Place() puts it somewhere. Destroy() takes a thing (and
+// anything in it) out of the game for good, cleaning up after it everywhere
+// it might be remembered.
+//
+// A Spawner keeps a room (or a container) stocked with copies of a
+// prototype: every so often, if there are fewer than its maximum there, it
+// makes another. In world files:
+//
+//  ["spawner", "tag", "proto_ref", "place_ref", "side", max, secs (, "message") ]
+//
+// where the message (if any) is told to the room when a copy appears.
+// Copies made by a Spawner have references beginning with its tag, which is
+// how it knows which things are its own.
+//
+package factory
+
+import( "fmt"; "strings";
+        "dta5/act"; "dta5/desc"; "dta5/log"; "dta5/msg"; "dta5/ref";
+        "dta5/room"; "dta5/save"; "dta5/scripts"; "dta5/thing";
+)
+
+func log(lvl dtalog.LogLvl, fmtstr string, args ...interface{}) {
+  dtalog.Log(lvl, fmt.Sprintf("factory: " + fmtstr, args...))
+}
+
+// The prefix for references of things Spawn()ed without one.
+//
+var DefaultPrefix string = "f"
+
+var counters = make(map[string]int)
+
+// All loaded Spawners, by tag, so they can be Arm()ed after loading.
+//
+var Spawners = make(map[string]*Spawner)
+
+// This function prepares the package for loading the game. This should be
+// called both on initial game loading and when loading a saved game state.
+//
+func Initialize() {
+  counters = make(map[string]int)
+  Spawners = make(map[string]*Spawner)
+}
+
+// NewRef() returns an unused reference string beginning with prefix.
+//
+func NewRef(prefix string) string {
+  for {
+    n := counters[prefix]
+    counters[prefix] = n + 1
+    r := fmt.Sprintf("%s%d", prefix, n)
+    if ref.Deref(r) == nil {
+      return r
+    }
+  }
+}
+
+// SetSerial() sets the next number NewRef() will try for prefix (unless
+// it's already past that). It's meant to be called by the dta5/load package.
+//
+func SetSerial(prefix string, n int) {
+  if n > counters[prefix] {
+    counters[prefix] = n
+  }
+}
+
+// Save() saves the counters, and the Spawners' prototypes (which, not
+// being anywhere in the world, wouldn't get saved otherwise). Spawners
+// themselves are permanent, and come from the world file.
+//
+func Save(s save.Saver) {
+  for prefix, n := range counters {
+    s.Encode([]interface{}{ "serial", prefix, n, })
+  }
+  for _, sp := range Spawners {
+    if proto, ok := ref.Deref(sp.ProtoRef).(thing.Thing); ok && (proto.Loc().Place == nil) {
+      proto.Save(s)
+    }
+  }
+}
+
+// Spawn() makes a copy of proto with a new reference beginning with prefix
+// (or DefaultPrefix, if it's ""), or returns nil if proto can't be copied.
+// The copy isn't anywhere yet.
+//
+func Spawn(proto thing.Thing, prefix string) thing.Thing {
+  if prefix == "" {
+    prefix = DefaultPrefix
+  }
+  t := thing.Clone(proto, NewRef(prefix))
+  if t == nil {
+    log(dtalog.ERR, "Spawn(%q): unable to copy", proto.Ref())
+  }
+  return t
+}
+
+// sideOf() returns the ThingList on the given side of place (a room.Room
+// or a thing.Container), or nil.
+//
+func sideOf(place ref.Interface, side byte) *thing.ThingList {
+  switch p := place.(type) {
+  case *room.Room:
+    if side == room.SCENERY {
+      return p.Scenery
+    }
+    return p.Contents
+  case thing.Container:
+    return p.Side(side)
+  }
+  return nil
+}
+
+// Place() puts t on the given side of place (a room.Room or a
+// thing.Container), whether it fits or not.
+//
+func Place(t thing.Thing, place ref.Interface, side byte) error {
+  tl := sideOf(place, side)
+  if tl == nil {
+    return fmt.Errorf("%q has nowhere for things on side %d", place.Ref(), side)
+  }
+  tl.Add(t)
+  return nil
+}
+
+// Things that hold things in some way other than in a ThingList (like a
+// pc.PlayerChar holding things in its hands) should implement Releaser so
+// Destroy() can take things away from them.
+//
+type Releaser interface {
+  Release(thing.Thing)
+}
+
+// Destroy() removes t from wherever it is and from every registry that
+// might remember it: script bindings, ref.Data, desc.Limbo, and finally
+// the ref registry itself. Anything in t is Destroy()ed first.
+//
+func Destroy(t thing.Thing) {
+  if c, ok := t.(thing.Container); ok {
+    for _, s := range []byte{ thing.IN, thing.ON, thing.BEHIND, thing.UNDER, } {
+      if tl := c.Side(s); tl != nil {
+        inside := make([]thing.Thing, len(tl.Things))
+        copy(inside, tl.Things)
+        for _, x := range inside {
+          Destroy(x)
+        }
+      }
+    }
+  }
+
+  lv := t.Loc()
+  switch p := lv.Place.(type) {
+  case nil:
+  case Releaser:
+    p.Release(t)
+  default:
+    if tl := sideOf(p, lv.Side); tl != nil {
+      tl.Remove(t)
+    }
+  }
+
+  scripts.UnbindAll(t)
+  ref.ClearData(t)
+  delete(desc.Limbo, t.Ref())
+  ref.Deregister(t)
+  log(dtalog.DBG, "Destroy(%q): done", t.Ref())
+}
+
+type Spawner struct {
+  Tag      string
+  ProtoRef string
+  PlaceRef string
+  Side     byte
+  Max      int
+  Delay    float64
+  Message  string
+}
+
+// NewSpawner() makes a Spawner; it's meant to be called by the dta5/load
+// package. It won't do anything until it's Arm()ed.
+//
+func NewSpawner(tag, protoRef, placeRef string, side byte, max int,
+                delay float64, message string) *Spawner {
+  if _, ok := Spawners[tag]; ok {
+    log(dtalog.WRN, "NewSpawner(%q): replacing existing spawner", tag)
+  }
+  sp := &Spawner{ Tag: tag, ProtoRef: protoRef, PlaceRef: placeRef, Side: side,
+                  Max: max, Delay: delay, Message: message, }
+  Spawners[tag] = sp
+  return sp
+}
+
+// Count() returns how many of the Spawner's copies are where it puts them.
+//
+func (sp *Spawner) Count() int {
+  place := ref.Deref(sp.PlaceRef)
+  if place == nil {
+    return 0
+  }
+  tl := sideOf(place, sp.Side)
+  if tl == nil {
+    return 0
+  }
+  var n int = 0
+  for _, t := range tl.Things {
+    if strings.HasPrefix(t.Ref(), sp.Tag + "-") {
+      n++
+    }
+  }
+  return n
+}
+
+// Arm() schedules the Spawner's next check (each one schedules the next).
+//
+func (sp *Spawner) Arm() {
+  if sp.Delay <= 0 {
+    log(dtalog.WRN, "(*Spawner %q) Arm(): interval must be positive", sp.Tag)
+    return
+  }
+  act.Add(sp.Delay, sp.tick)
+}
+
+func (sp *Spawner) tick() error {
+  if Spawners[sp.Tag] != sp {
+    return nil
+  }
+  defer sp.Arm()
+  if sp.Count() >= sp.Max {
+    return nil
+  }
+
+  proto, ok := ref.Deref(sp.ProtoRef).(thing.Thing)
+  if !ok {
+    log(dtalog.WRN, "(*Spawner %q) tick(): no prototype %q", sp.Tag, sp.ProtoRef)
+    return nil
+  }
+  place := ref.Deref(sp.PlaceRef)
+  if place == nil {
+    log(dtalog.WRN, "(*Spawner %q) tick(): no place %q", sp.Tag, sp.PlaceRef)
+    return nil
+  }
+  t := Spawn(proto, sp.Tag + "-")
+  if t == nil {
+    return nil
+  }
+  if err := Place(t, place, sp.Side); err != nil {
+    log(dtalog.WRN, "(*Spawner %q) tick(): %s", sp.Tag, err)
+    Destroy(t)
+    return nil
+  }
+
+  if sp.Message != "" {
+    if r := RoomOf(place); r != nil {
+      r.Deliver(msg.New("txt", "%s", sp.Message))
+    }
+  }
+  return nil
+}
+
+// RoomOf() returns the room.Room x is (or is in, however deeply), or nil
+// if it isn't in one.
+//
+func RoomOf(x ref.Interface) *room.Room {
+  // The depth limit guards against things somehow containing each other.
+  for n := 0; (x != nil) && (n < 64); n++ {
+    switch v := x.(type) {
+    case *room.Room:
+      return v
+    case thing.Thing:
+      x = v.Loc().Place
+    default:
+      return nil
+    }
+  }
+  return nil
+}
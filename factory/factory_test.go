@@ -0,0 +1,45 @@
+// factory_test.go
+//
+// Test suite for dta5/factory
+//
+package factory
+
+import( "testing";
+        "dta5/ref"; "dta5/room"; "dta5/scripts"; "dta5/thing";
+)
+
+func TestSpawnAndDestroy(t *testing.T) {
+  Initialize()
+  r := room.NewRoom("ft-r0", "A Test Room")
+  proto := thing.NewItem("ft-proto", "a widget", "", false, 1.0, 1.0)
+  thing.NewItem("w0", "a squatter", "", false, 1.0, 1.0)
+
+  c := Spawn(proto, "w")
+  if c == nil || c.Ref() != "w1" {
+    t.Fatalf("Spawn(): got %v, want a copy with ref \"w1\"", c)
+  }
+  if err := Place(c, r, room.CONTENTS); err != nil {
+    t.Fatalf("Place(): %s", err)
+  }
+  c.SetData("k", "v")
+  scripts.Scripts["ft_noop"] = func(obj, subj, dobj, iobj thing.Thing,
+                                    verb, prep, text string) bool { return true }
+  scripts.Bind(c, "get", "ft_noop")
+
+  Destroy(c)
+  if r.Contents.Contains(c) {
+    t.Errorf("Destroy(): still in the room")
+  }
+  if ref.Deref("w1") != nil {
+    t.Errorf("Destroy(): still registered")
+  }
+  if _, ok := ref.Data["w1"]; ok {
+    t.Errorf("Destroy(): data left behind")
+  }
+  if _, ok := scripts.Bindings["w1"]; ok {
+    t.Errorf("Destroy(): bindings left behind")
+  }
+  if n := NewRef("w"); n != "w2" {
+    t.Errorf("NewRef(): got %q, want \"w2\" (refs shouldn't be reused)", n)
+  }
+}
@@ -2,7 +2,7 @@
 //
 // extra dta5 world-building shortcuts
 //
-// updated 2017-08-18
+// updated 2026-10-19
 //
 package build
 
@@ -20,6 +20,7 @@ var Funx = map[string]Func {
   "key":        MakeKeyAndLocker,
   "autoclose":  MakeAutoClosing,
   "cvmd":       AddCannotDirectMessage,
+  "seats":      SetSeats,
 }
 
 func Build(data []interface{}) error {
@@ -35,20 +36,33 @@ func Build(data []interface{}) error {
 
 // MakeKeyAndLocker()
 //
-// ["key_ref", "locker_ref", is_initially_locked ]
+// ["key_ref", "locker_ref", is_initially_locked [, difficulty] ]
+//
+// Gives the locker (a thing.Lockable) a thing.Lock, if it doesn't already
+// have one, and makes the key fit it. Using this more than once on the same
+// locker gives it several keys.
 //
 func MakeKeyAndLocker(data []interface{}) error {
   keyRef   := data[0].(string)
   lockRef  := data[1].(string)
   isLocked := data[2].(bool)
   
-  lock := ref.Deref(lockRef).(thing.Thing)
+  lockr, ok := ref.Deref(lockRef).(thing.Lockable)
+  if !ok {
+    log(dtalog.ERR, "MakeKeyAndLocker(): %q is not a thing.Lockable", lockRef)
+    return fmt.Errorf("%q cannot have a lock", lockRef)
+  }
   
-  lock.SetData("locked_script_unlocked", !isLocked)
-  lock.SetData("lock_unlock_script_key", keyRef)
-  scripts.Bind(lock, "open", "locked_script")
-  scripts.Bind(lock, "lock", "lock_unlock_script")
-  scripts.Bind(lock, "unlock", "lock_unlock_script")
+  lock := lockr.Lock()
+  if lock == nil {
+    lock = &thing.Lock{ Difficulty: thing.DefaultDifficulty, }
+    lockr.SetLock(lock)
+  }
+  lock.Locked = isLocked
+  lock.AddKey(keyRef)
+  if len(data) > 3 {
+    lock.Difficulty = int(data[3].(float64))
+  }
   return nil
 }
 
@@ -81,3 +95,23 @@ func AddCannotDirectMessage(data []interface{}) error {
   scripts.Bind(obj, verb, "CVMD")
   return nil
 }
+
+// SetSeats()
+//
+// ["key_ref", n]
+//
+// Sets how many people can sit (or lie, or kneel) on a piece of furniture
+// (see dta5/pc).
+//
+func SetSeats(data []interface{}) error {
+  key_ref := data[0].(string)
+  n       := data[1].(float64)
+  
+  obj := ref.Deref(key_ref)
+  if obj == nil {
+    log(dtalog.ERR, "SetSeats(): %q does not exist", key_ref)
+    return fmt.Errorf("%q does not exist", key_ref)
+  }
+  obj.SetData("seats", n)
+  return nil
+}
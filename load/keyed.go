@@ -0,0 +1,367 @@
+// keyed.go
+//
+// dta5 keyed (named-field) world file records
+//
+// updated 2026-10-19
+//
+// Besides the positional lists described in load.go, world files can
+// contain records that are JSON objects, with the type of record under
+// "type" and each field under its name, in any order:
+//
+//  {"type": "room", "ref": "r1", "title": "East Shelter",
+//   "exits": {"north": "r1-t2", "south": "r1-t3"}}
+//  {"type": "item", "ref": "r1-t4", "name": "an/a antique brass key",
+//   "prep": "", "plural": false, "mass": 0.1, "bulk": 0.05}
+//
+// The names of each type's fields are in recordFields below (those of
+// thing records are the fieldNames in proto.go, after "ref"). A field whose
+// name ends in "..." stands for the rest of the positional record, and is
+// a list; fields that are optional in positional records can be left out.
+// Rooms' exits are a map of direction names to targets. Prototypes and
+// instances have their (changed) fields at the top level:
+//
+//  {"type": "proto", "tag": "key", "from": "item", "name": "a small metal key",
+//   "prep": "", "plural": false, "mass": 0.01, "bulk": 0.005}
+//  {"type": "inst", "ref": "r3-t3", "proto": "key"}
+//
+// Keyed records are turned into positional ones (see Positional()) before
+// they're loaded, so the two formats can be mixed freely. Keyed() goes the
+// other way; see dta5/wconv for a program that uses it to convert world
+// files.
+//
+package load
+
+import( "bytes"; "encoding/json"; "fmt"; "sort"; "strings";
+        "dta5/room";
+)
+
+// The names of the fields of each type of record (after the type), in
+// order. Thing records, "room", "proto", and "inst" are handled separately.
+//
+var recordFields = map[string][]string {
+  "dwy":     { "ref", "name", "prep", "plural", "mass", "bulk", "toggle", },
+  "door":    { "doorway", "other", "open", },
+  "coins":   { "ref", "count", },
+  "shop":    { "ref", "name", "prep", "restock", "buy_rate", "serial", "stock", },
+  "pop":     { "ref", "side", "things...", },
+  "lock":    { "ref", "locked", "difficulty", "keys...", },
+  "value":   { "ref", "value", },
+  "descas":  { "ref", "as", },
+  "hide":    { "ref", "concealment", },
+  "exit":    { "room", "name", "target", "verb", "aliases...", },
+  "landmark": { "room", "names...", },
+  "hidexit": { "room", "direction", "concealment", },
+  "dark":    { "room", "darkness", },
+  "spawner": { "tag", "proto", "place", "side", "max", "secs", "message", },
+  "vehicle": { "ref", "interior", "doorway", "route...", },
+  "serial":  { "prefix", "number", },
+  "mood":    { "min_secs", "max_secs", "rooms", "messages...", },
+  "zone":    { "tag", "name", "settings", "rooms...", },
+  "bind":    { "ref", "verb", "script", "priority", },
+  "script":  { "ref", "verb", "script", "priority", },
+  "timer":   { "ref", "secs", },
+  "quest":   { "tag", "title", "stages", },
+  "attr":    { "tag", "name", "default", },
+  "skill":   { "tag", "name", "attr", "default", },
+  "body":    { "tag", "mass", "bulk", "worn", },
+  "build":   { "func", "args...", },
+  "data":    { "data", },
+  "load":    { "file", },
+  "rem":     { "text...", },
+}
+
+// fieldsOf() returns the names of the fields of the given type of record,
+// or nil if it's not a type with a fixed list of fields.
+//
+func fieldsOf(kind string) []string {
+  if names, ok := recordFields[kind]; ok {
+    return names
+  }
+  if names, ok := fieldNames[kind]; ok {
+    return append([]string{ "ref", }, names...)
+  }
+  return nil
+}
+
+// A Field is one named field of a keyed record.
+//
+type Field struct {
+  Name  string
+  Value interface{}
+}
+
+// A Record is a keyed record with its fields in order, starting with
+// "type". It's written as a JSON object with its keys in that order.
+//
+type Record []Field
+
+func (r Record) MarshalJSON() ([]byte, error) {
+  var buff bytes.Buffer
+  buff.WriteString("{")
+  for n, f := range r {
+    if n > 0 {
+      buff.WriteString(", ")
+    }
+    k, err := marshal(f.Name)
+    if err != nil {
+      return nil, err
+    }
+    v, err := marshal(f.Value)
+    if err != nil {
+      return nil, err
+    }
+    buff.Write(k)
+    buff.WriteString(": ")
+    buff.Write(v)
+  }
+  buff.WriteString("}")
+  return buff.Bytes(), nil
+}
+
+// marshal() is json.Marshal() without escaping HTML characters (which show
+// up in descriptions and messages).
+//
+func marshal(x interface{}) ([]byte, error) {
+  var buff bytes.Buffer
+  ncdr := json.NewEncoder(&buff)
+  ncdr.SetEscapeHTML(false)
+  if err := ncdr.Encode(x); err != nil {
+    return nil, err
+  }
+  return bytes.TrimRight(buff.Bytes(), "\n"), nil
+}
+
+// dirByName() returns the room.NavDir with the given name.
+//
+func dirByName(name string) (room.NavDir, bool) {
+  for d, n := range room.NavDirNames {
+    if n == name {
+      return d, true
+    }
+  }
+  return 0, false
+}
+
+// Positional() turns a keyed record into the equivalent positional one.
+//
+func Positional(m map[string]interface{}) ([]interface{}, error) {
+  kind, ok := m["type"].(string)
+  if !ok {
+    return nil, fmt.Errorf("record has no \"type\"")
+  }
+  x := []interface{}{ kind, }
+
+  switch kind {
+  case "room":
+    if err := checkFields(m, []string{ "ref", "title", "exits", }); err != nil {
+      return nil, err
+    }
+    r, ok1 := m["ref"].(string)
+    title, ok2 := m["title"].(string)
+    if !ok1 || !ok2 {
+      return nil, fmt.Errorf("room record needs \"ref\" and \"title\"")
+    }
+    x = append(x, r, title)
+    exits, _ := m["exits"].(map[string]interface{})
+    targs := make([]interface{}, 0, len(room.NavDirNames))
+    for dir, targ := range exits {
+      d, ok := dirByName(dir)
+      if !ok {
+        return nil, fmt.Errorf("%q is not a direction", dir)
+      }
+      for len(targs) <= int(d) {
+        targs = append(targs, "")
+      }
+      targs[d] = targ
+    }
+    return append(x, targs...), nil
+
+  case "proto":
+    x = append(x, m["tag"], m["from"])
+    from, _ := m["from"].(string)
+    if names, ok := fieldNames[from]; ok {
+      if err := checkFields(m, names, "tag", "from"); err != nil {
+        return nil, err
+      }
+      fields, err := fieldValues(m, names)
+      if err != nil {
+        return nil, err
+      }
+      return append(x, fields...), nil
+    }
+    return append(x, rest(m, "type", "tag", "from")), nil
+
+  case "inst":
+    x = append(x, m["ref"], m["proto"])
+    if ovr := rest(m, "type", "ref", "proto"); len(ovr) > 0 {
+      x = append(x, ovr)
+    }
+    return x, nil
+  }
+
+  names := fieldsOf(kind)
+  if names == nil {
+    return nil, fmt.Errorf("unknown record type %q", kind)
+  }
+  if err := checkFields(m, names); err != nil {
+    return nil, err
+  }
+  fields, err := fieldValues(m, names)
+  if err != nil {
+    return nil, err
+  }
+  return append(x, fields...), nil
+}
+
+// checkFields() returns an error if m has any fields (other than "type")
+// that aren't in names or also.
+//
+func checkFields(m map[string]interface{}, names []string, also ...string) error {
+  known := map[string]bool{ "type": true, }
+  for _, n := range names {
+    known[strings.TrimSuffix(n, "...")] = true
+  }
+  for _, n := range also {
+    known[n] = true
+  }
+  for k := range m {
+    if !known[k] {
+      return fmt.Errorf("%q records have no field %q", m["type"], k)
+    }
+  }
+  return nil
+}
+
+// fieldValues() returns the values of the named fields from m, in order;
+// fields can be left off the end, but not out of the middle.
+//
+func fieldValues(m map[string]interface{}, names []string) ([]interface{}, error) {
+  vals := make([]interface{}, 0, len(names))
+  var missing string
+  for _, n := range names {
+    key := strings.TrimSuffix(n, "...")
+    v, ok := m[key]
+    if !ok {
+      if missing == "" {
+        missing = key
+      }
+      continue
+    }
+    if missing != "" {
+      return nil, fmt.Errorf("%q given without %q", key, missing)
+    }
+    if key != n {
+      lst, ok := v.([]interface{})
+      if !ok {
+        return nil, fmt.Errorf("%q is not a list", key)
+      }
+      vals = append(vals, lst...)
+    } else {
+      vals = append(vals, v)
+    }
+  }
+  return vals, nil
+}
+
+// rest() returns the fields of m other than those named.
+//
+func rest(m map[string]interface{}, except ...string) map[string]interface{} {
+  r := make(map[string]interface{})
+  for k, v := range m {
+    r[k] = v
+  }
+  for _, k := range except {
+    delete(r, k)
+  }
+  return r
+}
+
+// Keyed() turns a positional record into the equivalent keyed one.
+//
+func Keyed(x []interface{}) (Record, error) {
+  if len(x) == 0 {
+    return nil, fmt.Errorf("empty record")
+  }
+  kind, ok := x[0].(string)
+  if !ok {
+    return nil, fmt.Errorf("record type %v is not a string", x[0])
+  }
+  rec := Record{ { "type", kind, }, }
+  data := x[1:]
+
+  switch kind {
+  case "room":
+    if len(data) < 2 {
+      return nil, fmt.Errorf("room record too short")
+    }
+    rec = append(rec, Field{ "ref", data[0], }, Field{ "title", data[1], })
+    exits := make(map[string]interface{})
+    for n, targ := range data[2:] {
+      if targ != "" {
+        exits[room.NavDirNames[room.NavDir(n)]] = targ
+      }
+    }
+    return append(rec, Field{ "exits", exits, }), nil
+
+  case "proto":
+    if len(data) < 2 {
+      return nil, fmt.Errorf("proto record too short")
+    }
+    rec = append(rec, Field{ "tag", data[0], }, Field{ "from", data[1], })
+    from, _ := data[1].(string)
+    if names, ok := fieldNames[from]; ok {
+      return namedFields(rec, names, data[2:])
+    }
+    return withOverrides(rec, data, 2)
+
+  case "inst":
+    if len(data) < 2 {
+      return nil, fmt.Errorf("inst record too short")
+    }
+    rec = append(rec, Field{ "ref", data[0], }, Field{ "proto", data[1], })
+    return withOverrides(rec, data, 2)
+  }
+
+  names := fieldsOf(kind)
+  if names == nil {
+    return nil, fmt.Errorf("unknown record type %q", kind)
+  }
+  return namedFields(rec, names, data)
+}
+
+// namedFields() appends to rec the values in data, named by names.
+//
+func namedFields(rec Record, names []string, data []interface{}) (Record, error) {
+  for n, name := range names {
+    if n >= len(data) {
+      break
+    }
+    if key := strings.TrimSuffix(name, "..."); key != name {
+      return append(rec, Field{ key, data[n:], }), nil
+    }
+    rec = append(rec, Field{ name, data[n], })
+  }
+  if len(data) > len(names) {
+    return nil, fmt.Errorf("%q record has too many fields", rec[0].Value)
+  }
+  return rec, nil
+}
+
+// withOverrides() appends to rec the fields in the map at data[n] (if
+// any), sorted by name.
+//
+func withOverrides(rec Record, data []interface{}, n int) (Record, error) {
+  ovr, err := overrides(data, n)
+  if err != nil {
+    return nil, err
+  }
+  keys := make([]string, 0, len(ovr))
+  for k := range ovr {
+    keys = append(keys, k)
+  }
+  sort.Strings(keys)
+  for _, k := range keys {
+    rec = append(rec, Field{ k, ovr[k], })
+  }
+  return rec, nil
+}
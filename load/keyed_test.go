@@ -0,0 +1,143 @@
+// keyed_test.go
+//
+// Test suite for dta5/load keyed records
+//
+package load
+
+import( "encoding/json"; "reflect"; "testing";
+)
+
+// One positional record of every type, as it'd be found in a world file.
+//
+var positionalRecords = []string{
+  `["room", "r1", "East Shelter", "r1-t2", "", "r2"]`,
+  `["item", "r1-t4", "an/a antique brass key", "", false, 0.1, 0.05]`,
+  `["itemc", "r1-t5", "a battered bin", "", false, "x", 2, true, true, "io"]`,
+  `["cloth", "r1-t6", "a wool hat", "", false, 0.1, 0.2, "head"]`,
+  `["clothc", "r1-t7", "a canvas pack", "", false, 0.5, 1, "back", true, false, 10, 8]`,
+  `["light", "r1-t8", "a lantern", "", false, 1, 1, 3600, false]`,
+  `["consumable", "r1-t9", "an apple", "", false, 0.2, 0.2, "eat", 3, [["heal", 2]]]`,
+  `["stack", "r1-t10", "a/some arrow", "", false, 0.05, 0.1, "arrow", 12]`,
+  `["proto", "key", "item", "a small metal key", "", false, 0.01, 0.005]`,
+  `["proto", "gold_key", "key", {"name": "a small gold key", "mass": 0.02}]`,
+  `["inst", "r3-t3", "key"]`,
+  `["inst", "r3-t4", "key", {"prep": "on a string"}]`,
+  `["dwy", "r1-t2", "a wooden door", "", false, "x", "x", true]`,
+  `["door", "r1-t2", "r2-t1", false]`,
+  `["coins", "r1-t11", 25]`,
+  `["shop", "r2-t3", "a counter", "", 600, 0.5, "s2", ["key", "apple"]]`,
+  `["pop", "r1", "s", "r1-t4", "r1-t5"]`,
+  `["lock", "r1-t2", true, 20, "r1-t4", "r3-t3"]`,
+  `["value", "r1-t4", 15]`,
+  `["descas", "r3-t4", "r3-t3"]`,
+  `["hide", "r1-t4", 30]`,
+  `["exit", "r10", "the hole", "r10-t1", "climb up through", "climb up", "scramble up"]`,
+  `["landmark", "r9", "Parking Lot", "the lot"]`,
+  `["hidexit", "r2", "north", 40]`,
+  `["dark", "r10", "dark"]`,
+  `["spawner", "rats", "rat", "r4", "c", 3, 300, "A rat scurries in."]`,
+  `["vehicle", "v1", "v1-r1", "v1-t1", "r1", 60, "r2", 60]`,
+  `["serial", "s", 100]`,
+  `["mood", 60, 300, ["r1", "r2"], "A bird sings.", "The wind picks up."]`,
+  `["zone", "park", "Branbury State Park", {"outdoors": true}, "r1", "r2"]`,
+  `["bind", "r10", "say", "chamber_echo", 5]`,
+  `["script", "r1-t4", "get", "key_get", 0]`,
+  `["timer", "r1-t8", 3600]`,
+  `["quest", "band", "The Iron Band", [{"Desc": "Find the band.", "Objectives": ["found"]}]]`,
+  `["attr", "str", "Strength", 10]`,
+  `["skill", "climb", "Climbing", "str", 0]`,
+  `["body", "human", 40, 60, {"head": 1, "back": 1}]`,
+  `["build", "key", "r1-t4", "r10-t2", true]`,
+  `["data", {"r1": {"visited": true}}]`,
+  `["load", "branbury.json"]`,
+  `["rem", "r10", "Underground Chamber"]`,
+}
+
+// TestKeyedRoundTrip checks that every positional record comes back the
+// same after being made keyed, written out, read back in, and made
+// positional again (which is what happens to a world file run through
+// wconv and then loaded).
+//
+func TestKeyedRoundTrip(t *testing.T) {
+  seen := make(map[string]bool)
+  for _, src := range positionalRecords {
+    var x []interface{}
+    if err := json.Unmarshal([]byte(src), &x); err != nil {
+      t.Fatalf("%s: json.Unmarshal(): %s", src, err)
+    }
+    seen[x[0].(string)] = true
+
+    rec, err := Keyed(x)
+    if err != nil {
+      t.Errorf("%s: Keyed(): %s", src, err)
+      continue
+    }
+    data, err := json.Marshal(rec)
+    if err != nil {
+      t.Errorf("%s: json.Marshal(): %s", src, err)
+      continue
+    }
+    var m map[string]interface{}
+    if err := json.Unmarshal(data, &m); err != nil {
+      t.Errorf("%s: json.Unmarshal(%s): %s", src, data, err)
+      continue
+    }
+    back, err := Positional(m)
+    if err != nil {
+      t.Errorf("%s: Positional(%s): %s", src, data, err)
+      continue
+    }
+    if !reflect.DeepEqual(back, x) {
+      t.Errorf("%s: keyed as %s, came back as %v", src, data, back)
+    }
+  }
+
+  for kind := range recordFields {
+    if !seen[kind] {
+      t.Errorf("no %q record tested", kind)
+    }
+  }
+  for kind := range fieldNames {
+    if !seen[kind] {
+      t.Errorf("no %q record tested", kind)
+    }
+  }
+  for _, kind := range []string{ "room", "proto", "inst", } {
+    if !seen[kind] {
+      t.Errorf("no %q record tested", kind)
+    }
+  }
+}
+
+// TestKeyedErrors checks that malformed records are refused.
+//
+func TestKeyedErrors(t *testing.T) {
+  var bad_keyed = []string{
+    `{"ref": "r1-t4"}`,
+    `{"type": "nonesuch", "ref": "r1-t4"}`,
+    `{"type": "value", "ref": "r1-t4", "worth": 15}`,
+    `{"type": "lock", "ref": "r1-t2", "difficulty": 20}`,
+    `{"type": "pop", "ref": "r1", "side": "s", "things": "r1-t4"}`,
+    `{"type": "room", "ref": "r1", "title": "East", "exits": {"up-ish": "r2"}}`,
+  }
+  for _, src := range bad_keyed {
+    var m map[string]interface{}
+    if err := json.Unmarshal([]byte(src), &m); err != nil {
+      t.Fatalf("%s: json.Unmarshal(): %s", src, err)
+    }
+    if _, err := Positional(m); err == nil {
+      t.Errorf("Positional(%s): expected an error", src)
+    }
+  }
+
+  var bad_positional = [][]interface{}{
+    { },
+    { "nonesuch", "r1-t4", },
+    { "value", "r1-t4", 15.0, 16.0, },
+  }
+  for _, x := range bad_positional {
+    if _, err := Keyed(x); err == nil {
+      t.Errorf("Keyed(%v): expected an error", x)
+    }
+  }
+}
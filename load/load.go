@@ -2,7 +2,7 @@
 //
 // dta5 loading and world-building module
 //
-// updated 2017-08-12
+// updated 2026-10-19
 //
 // Worlds in dta5 are specified (and saved!) as JSON files; each JSON file
 // contains a series of lists. The first element in each list is a string
@@ -12,7 +12,8 @@
 // associated object or action.
 //
 // The various formats are listed here as a shorthand; see the individual
-// loadXXX() functions below for more details.
+// loadXXX() functions below for more details. Each can also be written as a
+// JSON object with named fields; see keyed.go.
 //
 // room.Room:
 // ["room", "ref", "title", "nav targets"... ]
@@ -27,21 +28,117 @@
 // thing.Clothing:
 // ["cloth", "ref", "artAdjNoun", "prepPhrase", plural, mass, bulk, "slot" ]
 //
+// thing.LightSource:
+// ["light", "ref", "artAdjNoun", "prepPhrase", plural, mass, bulk, fuel_secs, lit ]
+//
+// thing.Consumable:
+// ["consumable", "ref", "artAdjNoun", "prepPhrase", plural, mass, bulk,
+//                "eat"|"drink"|"use", portions, [ [ "effect", args... ]... ] ]
+//
 // thing.WornContainer:
 // ["clothc", "ref", "artAdjNoun", "prepPhrase", plural, mass, bulk,
 //            "slot", will_toggle, is_open, mass_held, bulk_held ]
 //
+// thing.Stack:
+// ["stack", "ref", "artAdjNoun", "prepPhrase", "plural", unit_mass, unit_bulk,
+//           "kind", count ]
+//
+// a prototype for any of the above thing records (see proto.go), made
+// either from the record's fields (those after "ref") or from another
+// prototype with some fields changed:
+// ["proto", "tag", "type", fields... ]
+// ["proto", "tag", "parent_tag" (, { "field": value... }) ]
+//
+// a thing made from a prototype, with some fields changed (saved games write
+// things made this way like this, too):
+// ["inst", "ref", "proto_tag" (, { "field": value... }) ]
+//
+// a pile of money (a thing.Stack of coins):
+// ["coins", "ref", count ]
+//
+// shop.Shop:
+// ["shop", "ref", "artAdjNoun", "prepPhrase", restock_secs, buy_rate, serial,
+//          [ ["proto_ref", price, count, max ]... ] ]
+//
 // door.Doorway:
 // ["dwy", "ref", "artAdjNoun", "prepPhrase", plural, mass, bulk, WillToggle ]
 //
 // to populate a Room or Container
 // ["pop", "ref", "side_string", "ref_list"... ]
 //
+// to give a thing (or a door) a lock
+// ["lock", "ref", locked, difficulty, "key_refs"... ]
+//
+// to set the value (in coins) of a thing
+// ["value", "ref", value ]
+//
+// to have a thing share the description of another (generally its prototype)
+// ["descas", "ref", "described_ref" ]
+//
+// to hide a thing, so it has to be found by searching (see dta5/stats)
+// ["hide", "ref", concealment ]
+//
+// to give a room an exit with a name, taken by "verb name", "go name", or
+// any of the aliases (the verb is "go" if it's "" or left out)
+// ["exit", "room_ref", "name", "target_ref" (, "verb" (, "alias"... )) ]
+//
+// to give a room names players can TRAVEL to it by
+// ["landmark", "room_ref", "name"... ]
+//
+// to hide a room's exit in the given direction ("north", "up", etc.)
+// ["hidexit", "room_ref", "direction", concealment ]
+//
+// to make a room dark ("dark") or dark at night ("night")
+// ["dark", "room_ref", "darkness" ]
+//
+// to keep a room or container stocked with copies of a prototype (see
+// dta5/factory)
+// ["spawner", "tag", "proto_ref", "place_ref", "side_string", max, secs (, "message") ]
+//
+// to make a thing a vehicle that carries passengers in its interior room
+// around a route of stops (see dta5/vehicle)
+// ["vehicle", "thing_ref", "interior_room_ref", "doorway_ref",
+//             [ "room_ref", secs, board ]... ]
+//
+// to set the next number used to make references with the given prefix
+// (saved games write these; see dta5/factory)
+// ["serial", "prefix", number ]
+//
+// to group rooms into a zone, with settings for all of them (see
+// dta5/zone and loadZone())
+// ["zone", "tag", "Name", { "outdoor": bool, "safe": bool, "reset": secs,
+//                           "mood": [ min_secs, max_secs, "messages"... ] },
+//          "room_refs"... ]
+//
 // to add a MoodMessaging object
 // ["mood", min_secs, max_secs, [ room_refs... ], [ messages...] ]
 //
-// to bind a script to a thing
-// ["bind", "obj_ref", "verb", "script_tag" ]
+// to bind a script to a thing (saved games write "script" instead of "bind";
+// both are accepted); an object can have several scripts per verb, which run
+// in order of priority (lowest first; the default is 0), and a verb of "*"
+// matches every verb. Saved games keep every binding (and timer), since
+// scripts can bind and unbind things during play, so loading one takes them
+// all from the save file, and none from the world files.
+// ["bind", "obj_ref", "verb", "script_tag" (, priority) ]
+//
+// to bind a script to run after an action, rather than before
+// ["bind", "obj_ref", "after:verb", "script_tag" ]
+//
+// to bind a trigger to a room (event is "enter", "leave", "say", or "timer")
+// ["bind", "room_ref", "event", "trigger_tag" ]
+//
+// to make a room fire its "timer" trigger every secs seconds
+// ["timer", "room_ref", secs ]
+//
+// to declare a quest (see dta5/quest)
+// ["quest", "tag", "Title", [ [ "stage description", "objective"... ]... ] ]
+//
+// to declare a character attribute or skill (see dta5/stats)
+// ["attr",  "tag", "Name", default_value ]
+// ["skill", "tag", "Name", "attr_tag", default_rank ]
+//
+// to declare a body type (see dta5/body)
+// ["body", "tag", carry_mass, carry_bulk, worn_bulk ]
 //
 // to use a world-building function from load/build
 // ["build", "func_tag", args ... ]
@@ -53,9 +150,9 @@
 package load
 
 import( "encoding/json"; "fmt"; "os"; "path/filepath";
-        "dta5/door"; "dta5/log"; "dta5/mood"; "dta5/ref";
-        "dta5/room"; "dta5/scripts"; "dta5/thing";
-        "dta5/load/build";
+        "dta5/body"; "dta5/door"; "dta5/factory"; "dta5/log"; "dta5/mood"; "dta5/quest"; "dta5/ref";
+        "dta5/room"; "dta5/scripts"; "dta5/shop"; "dta5/stats"; "dta5/thing";
+        "dta5/vehicle"; "dta5/load/build";
 )
 
 var WorldDir string
@@ -234,6 +331,53 @@ func loadClothing(data []interface{}) error {
   return nil
 }
 
+// loadLightSource()
+// [ ref, artAdjNoun, prepPhrase, plural, mass, bulk, fuel, lit ]
+//
+// Creates a thing.LightSource
+//   * ref, artAdjNoun, prepPhrase, plural, mass, bulk: see loadItem() above
+//   * fuel float: seconds it can burn before going out (negative for
+//         forever)
+//   * lit bool: whether it starts out lit
+//
+func loadLightSource(data []interface{}) error {
+  loadItem(data[:6])
+  nip := ref.Deref(data[0].(string)).(*thing.Item)
+  thing.MakeLightSource(nip, data[6].(float64), data[7].(bool))
+  return nil
+}
+
+// loadConsumable()
+// [ ref, artAdjNoun, prepPhrase, plural, mass, bulk, how, portions,
+//        [ [ effect_tag, args... ]... ] ]
+//
+// Creates a thing.Consumable
+//   * ref, artAdjNoun, prepPhrase, plural, mass, bulk: see loadItem() above
+//   * how string: "eat", "drink", or "use"
+//   * portions int: how many times it can be eaten/drunk/used
+//   * the effects of each portion (see dta5/effect)
+//
+func loadConsumable(data []interface{}) error {
+  loadItem(data[:6])
+  nip := ref.Deref(data[0].(string)).(*thing.Item)
+  how := data[6].(string)
+  switch how {
+  case thing.EAT, thing.DRINK, thing.USE:
+  default:
+    log(dtalog.ERR, "loadConsumable(%q): bad way of consuming %q", data[0], how)
+    return fmt.Errorf("%q is not a way of consuming something", how)
+  }
+  effs := make([]thing.Effect, 0, 0)
+  if len(data) > 8 {
+    for _, x := range data[8].([]interface{}) {
+      e := x.([]interface{})
+      effs = append(effs, thing.Effect{ Tag: e[0].(string), Args: e[1:], })
+    }
+  }
+  thing.MakeConsumable(nip, how, int(data[7].(float64)), effs)
+  return nil
+}
+
 // loadWornContainer()
 // [ ref, artAdjNoun, prepPhrase, plural, mass, bulk, slot,
 //        will_toggle, is_open, mass_held, bulk_held ]
@@ -256,6 +400,315 @@ func loadWornContainer(data []interface{}) error {
   return nil
 }
 
+// loadStack()
+// [ ref, artAdjNoun, prep, plural, unit_mass, unit_bulk, kind, count ]
+//
+// Creates a thing.Stack
+//   * ref, artAdjNoun, prep: see loadItem() above
+//   * plural string: the plural noun ("" to guess)
+//   * unit_mass, unit_bulk: the mass and bulk of one of the things, to be
+//         read by json2TVal()
+//   * kind string: Stacks of the same kind merge ("" to use the ref)
+//   * count int: how many there are
+//
+func loadStack(data []interface{}) error {
+  thing.NewStack(data[0].(string), data[1].(string), data[3].(string),
+                 data[2].(string), json2TVal(data[4]), json2TVal(data[5]),
+                 data[6].(string), int(data[7].(float64)))
+  return nil
+}
+
+// loadCoins()
+// [ ref, count ]
+//
+// Creates a thing.Stack of coins (a pile of money)
+//   * ref string: the pile's reference string
+//   * count int: how many coins are in it
+//
+func loadCoins(data []interface{}) error {
+  thing.NewCoins(data[0].(string), int(data[1].(float64)))
+  return nil
+}
+
+// loadShop()
+// [ ref, artAdjNoun, prep, restock_secs, buy_rate, serial,
+//        [ [ proto_ref, price, count, max ]... ] ]
+//
+// Creates a shop.Shop
+//   * ref, artAdjNoun, prep: see loadItem() above
+//   * restock_secs float: seconds between restocks of sold-out stock
+//   * buy_rate float: fraction of a thing's value the Shop pays for it
+//   * serial int: used to make copies' reference strings (0 in world files)
+//   * the final element lists the Shop's stock: the ref of the prototype
+//         (which must already be loaded), its price, the number on hand,
+//         and the maximum number on hand
+//
+func loadShop(data []interface{}) error {
+  sp := shop.New(data[0].(string), data[1].(string), data[2].(string),
+                 data[3].(float64), data[4].(float64), int(data[5].(float64)))
+  for _, x := range data[6].([]interface{}) {
+    st := x.([]interface{})
+    proto, ok := ref.Deref(st[0].(string)).(thing.Thing)
+    if !ok {
+      log(dtalog.ERR, "loadShop(%q): %q is not a loaded thing", data[0], st[0])
+      continue
+    }
+    sp.AddStock(proto, int(st[1].(float64)), int(st[2].(float64)),
+                int(st[3].(float64)))
+  }
+  return nil
+}
+
+// loadLock()
+// [ ref, locked, difficulty, key_refs... ]
+//
+// Gives a thing.Lockable a thing.Lock
+//   * ref string: the reference string of the thing.Lockable (for a
+//         door.Door, either of its Doorways)
+//   * locked bool: whether it starts locked
+//   * difficulty int: percent chance of an attempt to pick it failing
+//   * key_refs string...: references of the keys that fit it
+//
+func loadLock(data []interface{}) error {
+  lockr, ok := ref.Deref(data[0].(string)).(thing.Lockable)
+  if !ok {
+    log(dtalog.ERR, "loadLock(%q): not a thing.Lockable", data[0])
+    return fmt.Errorf("%q cannot have a lock", data[0])
+  }
+  nl := &thing.Lock{ Locked: data[1].(bool), Difficulty: int(data[2].(float64)),
+                     Keys: make([]string, 0, len(data)-3), }
+  for _, k := range data[3:] {
+    nl.Keys = append(nl.Keys, k.(string))
+  }
+  lockr.SetLock(nl)
+  return nil
+}
+
+// loadValue()
+// [ ref, value ]
+//
+// Sets the value of a thing.Valued (any thing.Item, basically)
+//   * ref string: the reference string of the thing
+//   * value int: its value in coins
+//
+func loadValue(data []interface{}) error {
+  v, ok := ref.Deref(data[0].(string)).(thing.Valued)
+  if !ok {
+    log(dtalog.ERR, "loadValue(%q): not a thing.Valued", data[0])
+    return fmt.Errorf("%q does not have a value", data[0])
+  }
+  v.SetValue(int(data[1].(float64)))
+  return nil
+}
+
+// loadHide()
+// [ ref, concealment ]
+//
+// Hides a thing.Concealable (any thing.Item, basically)
+//   * ref string: the reference string of the thing
+//   * concealment int: how hard it is to find (see dta5/stats)
+//
+func loadHide(data []interface{}) error {
+  r := ref.Deref(data[0].(string))
+  if r == nil {
+    // When loading a saved game, a non-permanent thing hidden in a world
+    // file isn't loaded yet; the save file will have its own "hide" record.
+    log(dtalog.DBG, "loadHide(%q): not (yet) loaded", data[0])
+    return nil
+  }
+  c, ok := r.(thing.Concealable)
+  if !ok {
+    log(dtalog.ERR, "loadHide(%q): not a thing.Concealable", data[0])
+    return fmt.Errorf("%q can't be hidden", data[0])
+  }
+  c.SetConcealment(int(data[1].(float64)))
+  return nil
+}
+
+// loadHiddenExit()
+// [ room_ref, direction, concealment ]
+//
+// Hides an exit from a room.Room
+//   * room_ref string: the reference string of the Room
+//   * direction string: the full name of the direction ("north", "up", etc.)
+//   * concealment int: how hard it is to find (see dta5/stats)
+//
+func loadHiddenExit(data []interface{}) error {
+  r, ok := ref.Deref(data[0].(string)).(*room.Room)
+  if !ok {
+    log(dtalog.ERR, "loadHiddenExit(%q): not a room.Room", data[0])
+    return fmt.Errorf("%q is not a room", data[0])
+  }
+  dir_name := data[1].(string)
+  for d, n := range room.NavDirNames {
+    if n == dir_name {
+      r.HideExit(d, int(data[2].(float64)))
+      return nil
+    }
+  }
+  log(dtalog.ERR, "loadHiddenExit(%q): bad direction %q", data[0], dir_name)
+  return fmt.Errorf("%q is not a direction", dir_name)
+}
+
+// loadExit()
+// [ room_ref, name, target_ref (, verb (, aliases... )) ]
+//
+// Adds a named room.Exit to a room.Room
+//   * room_ref string: the reference string of the Room
+//   * name string: what the exit is called ("the ladder")
+//   * target_ref string: the Room or door.Doorway it leads to
+//   * verb string: how one takes it ("climb"); "go" by default
+//   * aliases string...: other commands that take it ("climb up")
+//
+func loadExit(data []interface{}) error {
+  r, ok := ref.Deref(data[0].(string)).(*room.Room)
+  if !ok {
+    log(dtalog.ERR, "loadExit(%q): not a room.Room", data[0])
+    return fmt.Errorf("%q is not a room", data[0])
+  }
+  ex := &room.Exit{ Name: data[1].(string), Target: data[2].(string), }
+  if len(data) > 3 {
+    ex.Verb = data[3].(string)
+    for _, a := range data[4:] {
+      ex.Aliases = append(ex.Aliases, a.(string))
+    }
+  }
+  r.AddExit(ex)
+  return nil
+}
+
+// loadLandmark()
+// [ room_ref, names... ]
+//
+// Makes a room.Room a landmark (see room.Landmarks)
+//   * room_ref string: the reference string of the Room
+//   * names string...: the names players can TRAVEL to it by
+//
+func loadLandmark(data []interface{}) error {
+  r := data[0].(string)
+  if _, ok := ref.Deref(r).(*room.Room); !ok {
+    log(dtalog.ERR, "loadLandmark(%q): not a room.Room", r)
+    return fmt.Errorf("%q is not a room", r)
+  }
+  for _, n := range data[1:] {
+    room.AddLandmark(r, n.(string))
+  }
+  return nil
+}
+
+// loadDarkness()
+// [ room_ref, darkness ]
+//
+// Sets how dark a room is when nothing in it gives off light
+//   * room_ref string: the reference string of the room.Room
+//   * darkness string: "dark" (always), "night" (only at night), or "lit"
+//         (never)
+//
+func loadDarkness(data []interface{}) error {
+  r, ok := ref.Deref(data[0].(string)).(*room.Room)
+  if !ok {
+    log(dtalog.ERR, "loadDarkness(%q): not a room.Room", data[0])
+    return fmt.Errorf("%q is not a room", data[0])
+  }
+  switch data[1].(string) {
+  case "dark":
+    r.SetDarkness(room.DARK)
+  case "night":
+    r.SetDarkness(room.NIGHT_DARK)
+  case "lit":
+    r.SetDarkness(room.LIT)
+  default:
+    log(dtalog.ERR, "loadDarkness(%q): bad darkness %q", data[0], data[1])
+    return fmt.Errorf("%q is not a kind of darkness", data[1])
+  }
+  return nil
+}
+
+// loadSpawner()
+// [ tag, proto_ref, place_ref, side_string, max, secs (, message) ]
+//
+// Creates a factory.Spawner
+//   * tag string: identifies the spawner (and begins the references of the
+//         copies it makes)
+//   * proto_ref string: the reference string of the thing to copy
+//   * place_ref, side_string: where to put the copies (see populate())
+//   * max int: how many copies to keep there
+//   * secs float: how often to check
+//   * message string: (optional) what the room is told when a copy appears
+//
+func loadSpawner(data []interface{}) error {
+  var message string
+  if len(data) > 6 {
+    message = data[6].(string)
+  }
+  factory.NewSpawner(data[0].(string), data[1].(string), data[2].(string),
+                     str2side(data[3].(string)), int(data[4].(float64)),
+                     data[5].(float64), message)
+  return nil
+}
+
+// loadVehicle()
+// [ thing_ref, interior_ref, doorway_ref, [ room_ref, secs, board ]... ]
+//
+// Makes a thing into a vehicle.Vehicle
+//   * thing_ref string: the reference string of the thing that moves
+//   * interior_ref string: the reference string of the room inside it
+//   * doorway_ref string: the door.Doorway it's boarded through (either
+//         side); it's opened and closed as the vehicle comes and goes
+//   * the stops of its route, in order (it goes back to the first after
+//         the last):
+//       * room_ref string: the room the stop is in
+//       * secs float: how long the vehicle stays there
+//       * board bool: whether passengers can get on and off there
+//
+func loadVehicle(data []interface{}) error {
+  if len(data) < 4 {
+    log(dtalog.ERR, "loadVehicle(%q): argument slice not long enough", data)
+    return fmt.Errorf("argument slice %q not long enough", data)
+  }
+  route := make([]vehicle.Stop, 0, len(data) - 3)
+  for _, x := range data[3:] {
+    s, ok := x.([]interface{})
+    if !ok || (len(s) < 3) {
+      log(dtalog.ERR, "loadVehicle(%q): bad stop %v", data[0], x)
+      return fmt.Errorf("bad stop %v", x)
+    }
+    route = append(route, vehicle.Stop{ Room: s[0].(string), Secs: s[1].(float64),
+                                        Board: s[2].(bool), })
+  }
+  vehicle.New(data[0].(string), data[1].(string), data[2].(string), route)
+  return nil
+}
+
+// loadSerial()
+// [ prefix, number ]
+//
+// Sets the next number the factory package will use to make references
+// beginning with prefix.
+//
+func loadSerial(data []interface{}) error {
+  factory.SetSerial(data[0].(string), int(data[1].(float64)))
+  return nil
+}
+
+// loadDescAs()
+// [ ref, described_ref ]
+//
+// Makes a thing share the description of another thing
+//   * ref string: the reference string of the thing
+//   * described_ref string: the reference string of the thing whose
+//         description it should use
+//
+func loadDescAs(data []interface{}) error {
+  t, ok := ref.Deref(data[0].(string)).(interface{ SetDescRef(string) })
+  if !ok {
+    log(dtalog.ERR, "loadDescAs(%q): cannot set description reference", data[0])
+    return fmt.Errorf("%q cannot have its description reference set", data[0])
+  }
+  t.SetDescRef(data[1].(string))
+  return nil
+}
+
 // populate()
 // [ ref, side, refs... ]
 //
@@ -319,21 +772,125 @@ func loadMoodMessenger(data []interface{}) error {
   return nil
 }
 
+// loadAttr()
+// [ tag, name, default ]
+//
+// Declare a character attribute (see dta5/stats)
+//   * tag string: identifying string
+//   * name string: what players see it called
+//   * default float64: value for characters who don't have it yet
+//
+func loadAttr(data []interface{}) error {
+  stats.NewAttr(data[0].(string), data[1].(string), int(data[2].(float64)))
+  return nil
+}
+
+// loadSkill()
+// [ tag, name, attr_tag, default ]
+//
+// Declare a character skill (see dta5/stats)
+//   * tag string: identifying string
+//   * name string: what players see it called
+//   * attr_tag string: the attribute that governs the skill ("" for none)
+//   * default float64: rank for characters who don't have it yet
+//
+func loadSkill(data []interface{}) error {
+  stats.NewSkill(data[0].(string), data[1].(string), data[2].(string),
+                 int(data[3].(float64)))
+  return nil
+}
+
+// loadBody()
+// [ tag, mass, bulk, worn ]
+//
+// Declare a body type (see dta5/body)
+//   * tag string: identifying string
+//   * mass float64: how much mass the body can carry, all told
+//   * bulk float64: how much bulk the body can carry, all told
+//   * worn float64: how much of that bulk can be worn
+//
+func loadBody(data []interface{}) error {
+  if len(data) < 4 {
+    log(dtalog.ERR, "loadBody(%q): argument slice not long enough", data)
+    return fmt.Errorf("argument slice %q not long enough", data)
+  }
+  body.NewType(data[0].(string), float32(data[1].(float64)), float32(data[2].(float64)),
+               float32(data[3].(float64)))
+  return nil
+}
+
+// loadQuest()
+// [ tag, title, [ [ stage_desc, objectives... ]... ] ]
+//
+// Declare a quest.Quest
+//   * tag string: identifying string of the Quest
+//   * title string: what players see it called
+//   * stage_desc string: journal text for a stage
+//   * objectives string...: objectives to be achieved to pass the stage
+//
+func loadQuest(data []interface{}) error {
+  tag   := data[0].(string)
+  title := data[1].(string)
+  raw   := data[2].([]interface{})
+  
+  stages := make([]quest.Stage, 0, len(raw))
+  for _, x := range raw {
+    sdat := x.([]interface{})
+    stg := quest.Stage{ Desc: sdat[0].(string),
+                        Objectives: make([]string, 0, len(sdat) - 1), }
+    for _, o := range sdat[1:] {
+      stg.Objectives = append(stg.Objectives, o.(string))
+    }
+    stages = append(stages, stg)
+  }
+  
+  quest.New(tag, title, stages)
+  return nil
+}
+
 // bindScript()
-// [ obj_ref, verb, script_tag ]
+// [ obj_ref, verb, script_tag, (priority) ]
 //
-// Bind a script (see dta5/scripts) to a thing.Thing
-//   * ref string: reference of Thing to bind
-//   * verb string string: verb that will trigger the script
-//   * script_tag string: key in scripts.Scripts that maps to the function
-//         to bind
+// Bind a script (see dta5/scripts) to a thing.Thing, or a trigger to a
+// room.Room
+//   * ref string: reference of Thing (or Room) to bind
+//   * verb string string: verb (or event) that will trigger the script
+//   * script_tag string: key in scripts.Scripts (or scripts.Triggers) that
+//         maps to the function to bind
+//   * priority float64 (optional): lower-priority scripts run first (see
+//         scripts.Binding); defaults to 0
 //
 func bindScript(data []interface{}) error {
-  obj := ref.Deref(data[0].(string)).(thing.Thing)
+  obj := ref.Deref(data[0].(string))
+  if obj == nil {
+    log(dtalog.ERR, "bindScript(%q): no such referent", data[0])
+    return fmt.Errorf("cannot bind script to nonexistent %q", data[0])
+  }
   v   := data[1].(string)
   s   := data[2].(string)
   
-  scripts.Bind(obj, v, s)
+  if len(data) > 3 {
+    scripts.BindPriority(obj, v, s, int(data[3].(float64)))
+  } else {
+    scripts.Bind(obj, v, s)
+  }
+  return nil
+}
+
+// loadTimer()
+// [ place_ref, secs ]
+//
+// Makes a place fire its "timer" trigger (see dta5/scripts) periodically
+//   * place_ref string: reference of the place (generally a room.Room)
+//   * secs float64: number of seconds between firings
+//
+func loadTimer(data []interface{}) error {
+  place := ref.Deref(data[0].(string))
+  if place == nil {
+    log(dtalog.ERR, "loadTimer(%q): no such referent", data[0])
+    return fmt.Errorf("cannot set timer on nonexistent %q", data[0])
+  }
+  scripts.SetTimer(place, data[1].(float64))
   return nil
 }
 
@@ -367,18 +924,55 @@ var initialLoadMap = map[string]LoadFunc {
   "door":   loadDoor,
   "cloth":  loadClothing,
   "clothc": loadWornContainer,
+  "light":  loadLightSource,
+  "consumable": loadConsumable,
+  "stack":  loadStack,
+  "proto":  loadProto,
+  "inst":   loadInstance,
+  "coins":  loadCoins,
+  "shop":   loadShop,
+  "lock":   loadLock,
+  "value":  loadValue,
+  "descas": loadDescAs,
+  "hide":   loadHide,
+  "exit":   loadExit,
+  "landmark": loadLandmark,
+  "hidexit": loadHiddenExit,
+  "dark":   loadDarkness,
   "pop":    populate,
   "mood":   loadMoodMessenger,
+  "zone":   loadZone,
+  "spawner": loadSpawner,
+  "vehicle": loadVehicle,
   "script": bindScript,
+  "bind":   bindScript,
+  "timer":  loadTimer,
+  "quest":  loadQuest,
+  "attr":   loadAttr,
+  "skill":  loadSkill,
+  "body":   loadBody,
   "build":  build.Build,
   "data":   loadData,
+  "serial": loadSerial,
 }
 
 var permanentLoadMap = map[string]LoadFunc {
   "room":   loadRoom,
   "dwy":    loadDoorway,
+  "proto":  loadProto,
   "mood":   loadMoodMessenger,
-  "script": bindScript,
+  "zone":   loadZone,
+  "spawner": loadSpawner,
+  "vehicle": loadVehicle,
+  "quest":  loadQuest,
+  "attr":   loadAttr,
+  "skill":  loadSkill,
+  "body":   loadBody,
+  "hide":   loadHide,
+  "exit":   loadExit,
+  "landmark": loadLandmark,
+  "hidexit": loadHiddenExit,
+  "dark":   loadDarkness,
 }
 var mutableLoadMap = map[string]LoadFunc {
   "item":   loadItem,
@@ -386,8 +980,21 @@ var mutableLoadMap = map[string]LoadFunc {
   "door":   loadDoor,
   "cloth":  loadClothing,
   "clothc": loadWornContainer,
+  "light":  loadLightSource,
+  "consumable": loadConsumable,
+  "stack":  loadStack,
+  "inst":   loadInstance,
+  "coins":  loadCoins,
+  "shop":   loadShop,
+  "lock":   loadLock,
+  "value":  loadValue,
+  "descas": loadDescAs,
+  "hide":   loadHide,
   "pop":    populate,
+  "script": bindScript,
+  "timer":  loadTimer,
   "data":   loadData,
+  "serial": loadSerial,
 }
 
 // These values are used to specify what type of loading situation is
@@ -414,6 +1021,9 @@ func LoadFeature(x []interface{}, mode LoadType) error {
   var lf LoadFunc
   var ok bool
   cmd := x[0].(string)
+  if mode != MUT {
+    worldRecords = append(worldRecords, x)
+  }
   switch mode {
   case INIT:
     lf, ok = initialLoadMap[cmd]
@@ -446,13 +1056,30 @@ func LoadFile(path string, mode LoadType) error {
   dcdr := json.NewDecoder(f)
 
   for dcdr.More() {
-    var x []interface{}
-    err = dcdr.Decode(&x)
+    var raw interface{}
+    err = dcdr.Decode(&raw)
     if err != nil {
       log(dtalog.ERR, "LoadFile(%q): error in dcdr.Decode(): %s", path, err)
       return err
     }
     
+    var x []interface{}
+    switch rec := raw.(type) {
+    case []interface{}:
+      x = rec
+    case map[string]interface{}:
+      if x, err = Positional(rec); err != nil {
+        log(dtalog.ERR, "LoadFile(%q): bad keyed record %v: %s", path, rec, err)
+        continue
+      }
+    default:
+      log(dtalog.ERR, "LoadFile(%q): %v is not a record", path, raw)
+      continue
+    }
+    if len(x) == 0 {
+      continue
+    }
+    
     if x[0].(string) == "load" {
       LoadFile(filepath.Join(WorldDir, x[1].(string)), mode)
     } else {
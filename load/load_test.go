@@ -12,12 +12,12 @@ func init() {
 }
 
 func TestLoading(t *testing.T) {
-  err := LoadFile("tworld.json")
+  err := LoadFile("tworld.json", INIT)
   if err != nil {
     t.Errorf("error loading file\n")
   }
   
-  for k, v := range ref.Referents {
-    fmt.Printf("%q: %v\n", k, v)
-  }
+  ref.Walk(func(r ref.Interface) {
+    fmt.Printf("%q: %v\n", r.Ref(), r)
+  })
 }
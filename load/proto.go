@@ -0,0 +1,290 @@
+// proto.go
+//
+// dta5 prototypes and instances in world files
+//
+// updated 2026-10-19
+//
+// A prototype is a template for thing records: it has the record's type
+// ("item", "light", etc.) and values for its fields, but no reference, and
+// makes nothing by itself.
+//
+//  ["proto", "tag", "type", fields... ]
+//
+// where the fields are the ones that follow the reference in a record of
+// that type (see the fieldNames below for what they're called). A
+// prototype can also be made from another prototype, changing some of its
+// fields:
+//
+//  ["proto", "tag", "parent_tag" (, { "field": value, ... }) ]
+//
+// An instance makes a thing from a prototype, with a reference and
+// (optionally) some of the prototype's fields changed:
+//
+//  ["inst", "ref", "proto_tag" (, { "field": value, ... }) ]
+//
+// Prototypes come from world files; they aren't saved. Things that were
+// made as instances are saved as instances, with only the fields that are
+// different from their prototypes' (see rewriteInstance()).
+//
+package load
+
+import( "encoding/json"; "fmt";
+        "dta5/log"; "dta5/save";
+)
+
+type proto struct {
+  kind   string
+  fields []interface{}
+}
+
+// The names of the fields of each type of record that can have prototypes,
+// in order, after the reference.
+//
+var fieldNames = map[string][]string {
+  "item":  { "name", "prep", "plural", "mass", "bulk", },
+  "itemc": { "name", "prep", "plural", "mass", "bulk", "toggle", "open",
+             "sides", },
+  "cloth": { "name", "prep", "plural", "mass", "bulk", "slot", },
+  "clothc": { "name", "prep", "plural", "mass", "bulk", "slot", "toggle",
+              "open", "mass_held", "bulk_held", },
+  "light": { "name", "prep", "plural", "mass", "bulk", "fuel", "lit", },
+  "consumable": { "name", "prep", "plural", "mass", "bulk", "how",
+                  "portions", "effects", },
+  "stack": { "name", "prep", "plural", "unit_mass", "unit_bulk", "kind",
+             "count", },
+}
+
+// The loadXXX() functions for the above. (These can't be looked up in
+// initialLoadMap, because loadInstance() is in it.)
+//
+var protoLoaders = map[string]LoadFunc {
+  "item":       loadItem,
+  "itemc":      loadItemContainer,
+  "cloth":      loadClothing,
+  "clothc":     loadWornContainer,
+  "light":      loadLightSource,
+  "consumable": loadConsumable,
+  "stack":      loadStack,
+}
+
+var protos = make(map[string]*proto)
+
+// The tags of the prototypes of things made by loadInstance(), by ref.
+//
+var instances = make(map[string]string)
+
+func init() {
+  save.Rewrite = rewriteInstance
+}
+
+// Reset() forgets all prototypes and instances (and the world file's
+// records; see zone.go). It should be called before loading the game
+// (initially or from a save).
+//
+func Reset() {
+  protos = make(map[string]*proto)
+  instances = make(map[string]string)
+  worldRecords = nil
+}
+
+// fieldIndex() returns the position of the named field in a record of
+// the given type (not counting the type or the reference), or -1.
+//
+func fieldIndex(kind, field string) int {
+  for n, f := range fieldNames[kind] {
+    if f == field {
+      return n
+    }
+  }
+  return -1
+}
+
+// override() returns a copy of fields (of a record of the given type)
+// with the values in ovr replacing those of the named fields.
+//
+func override(kind string, fields []interface{}, ovr map[string]interface{}) ([]interface{}, error) {
+  nf := make([]interface{}, len(fieldNames[kind]))
+  copy(nf, fields)
+  for f, v := range ovr {
+    n := fieldIndex(kind, f)
+    if n < 0 {
+      return nil, fmt.Errorf("%q records have no field %q", kind, f)
+    }
+    nf[n] = v
+  }
+  return nf, nil
+}
+
+// overrides() returns the map of field values at data[n], or an empty map
+// if data is too short.
+//
+func overrides(data []interface{}, n int) (map[string]interface{}, error) {
+  if len(data) <= n {
+    return map[string]interface{}{}, nil
+  }
+  ovr, ok := data[n].(map[string]interface{})
+  if !ok {
+    return nil, fmt.Errorf("%v is not a map of fields", data[n])
+  }
+  return ovr, nil
+}
+
+// loadProto()
+// [ tag, type, fields... ]
+// [ tag, parent_tag (, { field: value... }) ]
+//
+// Declares a prototype
+//   * tag string: the prototype's tag, used by instances; it can't be the
+//         same as a record type
+//   * type string: the type of record ("item", "light", etc.), followed by
+//         the values of that record's fields (those after the reference)
+//   * parent_tag string: the tag of an already-declared prototype this one
+//         copies, with the given fields changed
+//
+func loadProto(data []interface{}) error {
+  if len(data) < 2 {
+    log(dtalog.ERR, "loadProto(%q): argument slice not long enough", data)
+    return fmt.Errorf("argument slice %q not long enough", data)
+  }
+  tag := data[0].(string)
+  from := data[1].(string)
+  if _, ok := fieldNames[tag]; ok {
+    log(dtalog.ERR, "loadProto(%q): tag is a record type", tag)
+    return fmt.Errorf("prototype tag %q is a record type", tag)
+  }
+  if _, ok := protos[tag]; ok {
+    log(dtalog.WRN, "loadProto(%q): replacing existing prototype", tag)
+  }
+
+  if names, ok := fieldNames[from]; ok {
+    fields := make([]interface{}, len(names))
+    copy(fields, data[2:])
+    protos[tag] = &proto{ kind: from, fields: fields, }
+    return nil
+  }
+
+  parent, ok := protos[from]
+  if !ok {
+    log(dtalog.ERR, "loadProto(%q): no record type or prototype %q", tag, from)
+    return fmt.Errorf("no record type or prototype %q", from)
+  }
+  ovr, err := overrides(data, 2)
+  if err == nil {
+    var fields []interface{}
+    if fields, err = override(parent.kind, parent.fields, ovr); err == nil {
+      protos[tag] = &proto{ kind: parent.kind, fields: fields, }
+      return nil
+    }
+  }
+  log(dtalog.ERR, "loadProto(%q): %s", tag, err)
+  return err
+}
+
+// loadInstance()
+// [ ref, proto_tag (, { field: value... }) ]
+//
+// Creates a thing from a prototype
+//   * ref string: the new thing's reference string
+//   * proto_tag string: the tag of the prototype
+//   * the optional final element gives values for fields that are
+//         different from the prototype's
+//
+func loadInstance(data []interface{}) error {
+  if len(data) < 2 {
+    log(dtalog.ERR, "loadInstance(%q): argument slice not long enough", data)
+    return fmt.Errorf("argument slice %q not long enough", data)
+  }
+  r := data[0].(string)
+  tag := data[1].(string)
+  p, ok := protos[tag]
+  if !ok {
+    log(dtalog.ERR, "loadInstance(%q): no prototype %q", r, tag)
+    return fmt.Errorf("no prototype %q", tag)
+  }
+  ovr, err := overrides(data, 2)
+  if err != nil {
+    log(dtalog.ERR, "loadInstance(%q): %s", r, err)
+    return err
+  }
+  fields, err := override(p.kind, p.fields, ovr)
+  if err != nil {
+    log(dtalog.ERR, "loadInstance(%q): %s", r, err)
+    return err
+  }
+  for n, v := range fields {
+    if v == nil {
+      log(dtalog.ERR, "loadInstance(%q): no value for field %q", r, fieldNames[p.kind][n])
+      return fmt.Errorf("no value for field %q", fieldNames[p.kind][n])
+    }
+  }
+
+  if err = protoLoaders[p.kind](append([]interface{}{ r, }, fields...)); err != nil {
+    return err
+  }
+  instances[r] = tag
+  return nil
+}
+
+// rewriteInstance() is the save.Rewrite function: it turns the record of a
+// thing that was made as an instance into an "inst" record with only the
+// fields that are different from its prototype's. Other records are
+// returned unchanged.
+//
+func rewriteInstance(x interface{}) interface{} {
+  rec, ok := x.([]interface{})
+  if !ok || (len(rec) < 2) {
+    return x
+  }
+  kind, _ := rec[0].(string)
+  r, _ := rec[1].(string)
+  tag, ok := instances[r]
+  if !ok {
+    return x
+  }
+  p, ok := protos[tag]
+  if !ok || (p.kind != kind) || (len(rec) - 2 != len(p.fields)) {
+    return x
+  }
+
+  diffs := make(map[string]interface{})
+  for n, v := range rec[2:] {
+    f := fieldNames[kind][n]
+    if !sameValue(comparable(f, v), comparable(f, p.fields[n])) {
+      diffs[f] = v
+    }
+  }
+  if len(diffs) == 0 {
+    return []interface{}{ "inst", r, tag, }
+  }
+  return []interface{}{ "inst", r, tag, diffs, }
+}
+
+// comparable() returns v (the value of the named field) in a form that can
+// be compared with sameValue(): side maps are keyed by single letters,
+// since "o" and "on" (for example) mean the same thing.
+//
+func comparable(field string, v interface{}) interface{} {
+  m, ok := v.(map[string]interface{})
+  if !ok || (field != "sides") {
+    return v
+  }
+  nm := make(map[string]interface{}, len(m))
+  for k, x := range m {
+    nm[k[:1]] = x
+  }
+  return nm
+}
+
+// sameValue() returns whether a and b would be saved as the same JSON.
+//
+func sameValue(a, b interface{}) bool {
+  aj, err := json.Marshal(a)
+  if err != nil {
+    return false
+  }
+  bj, err := json.Marshal(b)
+  if err != nil {
+    return false
+  }
+  return string(aj) == string(bj)
+}
@@ -0,0 +1,82 @@
+// proto_test.go
+//
+// Test suite for dta5/load prototypes and instances
+//
+package load
+
+import( "bytes"; "encoding/json"; "reflect"; "testing";
+        "dta5/ref"; "dta5/save";
+)
+
+// saved() returns the first record the thing with the given ref saves
+// (passed through save.Rewrite, if rewrite is true), as read back from JSON.
+//
+func saved(t *testing.T, r string, rewrite bool) interface{} {
+  si, ok := ref.Deref(r).(save.Interface)
+  if !ok {
+    t.Fatalf("%q is not a saveable thing", r)
+  }
+  if !rewrite {
+    defer func(rw func(interface{}) interface{}) { save.Rewrite = rw }(save.Rewrite)
+    save.Rewrite = nil
+  }
+  var buff bytes.Buffer
+  si.Save(save.Saver{ Encoder: json.NewEncoder(&buff), })
+
+  var x interface{}
+  if err := json.NewDecoder(&buff).Decode(&x); err != nil {
+    t.Fatalf("saving %q: %s", r, err)
+  }
+  return x
+}
+
+func TestInstanceRoundTrip(t *testing.T) {
+  ref.Reset()
+  Reset()
+
+  var records = []string{
+    `["key", "item", "a small metal key", "", false, 0.01, 0.005]`,
+    `["r3-t3", "key"]`,
+    `["r3-t4", "key", {"prep": "on a string"}]`,
+  }
+  var loaders = []LoadFunc{ loadProto, loadInstance, loadInstance, }
+  for n, src := range records {
+    var x []interface{}
+    if err := json.Unmarshal([]byte(src), &x); err != nil {
+      t.Fatalf("%s: json.Unmarshal(): %s", src, err)
+    }
+    if err := loaders[n](x); err != nil {
+      t.Fatalf("%s: %s", src, err)
+    }
+  }
+
+  var cases = []struct {
+    ref  string
+    want string
+  }{
+    { "r3-t3", `["inst", "r3-t3", "key"]`, },
+    { "r3-t4", `["inst", "r3-t4", "key", {"prep": "on a string"}]`, },
+  }
+  for _, c := range cases {
+    var want interface{}
+    if err := json.Unmarshal([]byte(c.want), &want); err != nil {
+      t.Fatalf("%s: json.Unmarshal(): %s", c.want, err)
+    }
+    full := saved(t, c.ref, false)
+    got := saved(t, c.ref, true)
+    if !reflect.DeepEqual(got, want) {
+      t.Errorf("%q: saved as %v, want %v", c.ref, got, want)
+      continue
+    }
+
+    // Loading the saved record should make the same thing again.
+    ref.Deregister(ref.Deref(c.ref))
+    if err := loadInstance(got.([]interface{})[1:]); err != nil {
+      t.Errorf("%q: reloading %v: %s", c.ref, got, err)
+      continue
+    }
+    if again := saved(t, c.ref, false); !reflect.DeepEqual(again, full) {
+      t.Errorf("%q: reloaded as %v, want %v", c.ref, again, full)
+    }
+  }
+}
@@ -0,0 +1,277 @@
+// zone.go
+//
+// dta5 loading and resetting zones
+//
+// updated 2026-10-19
+//
+// A zone (see dta5/zone) is put back the way the world file has it by
+// going over the world file's records again: every record loaded from the
+// world file (in INIT or PERM mode) is kept in worldRecords, and resetZone()
+// picks out the ones that make (and populate, and lock, and bind scripts
+// to) the things that start out in the zone, and loads them again.
+//
+package load
+
+import( "fmt";
+        "dta5/desc"; "dta5/door"; "dta5/factory"; "dta5/log"; "dta5/mood";
+        "dta5/ref"; "dta5/room"; "dta5/thing"; "dta5/vehicle"; "dta5/zone";
+)
+
+// Every record read from the world file, in order.
+//
+var worldRecords [][]interface{}
+
+func init() {
+  zone.Resetter = resetZone
+}
+
+// loadZone()
+// [ tag, name, { settings }, room_refs... ]
+//
+// Declares a zone.Zone
+//   * tag string: the zone's identifying string
+//   * name string: what players see it called
+//   * settings map: any of
+//       "outdoor" bool, "safe" bool: see dta5/zone
+//       "reset" float64: seconds between resets of the zone's things
+//       "mood" [ min_secs, max_secs, messages... ]: a mood.MoodMessenger
+//           covering all of the zone's rooms
+//   * room_refs string...: reference strings of the zone's rooms
+//
+func loadZone(data []interface{}) error {
+  if len(data) < 3 {
+    log(dtalog.ERR, "loadZone(%q): argument slice not long enough", data)
+    return fmt.Errorf("argument slice %q not long enough", data)
+  }
+  settings, ok := data[2].(map[string]interface{})
+  if !ok {
+    log(dtalog.ERR, "loadZone(%q): %v is not a map of settings", data[0], data[2])
+    return fmt.Errorf("%v is not a map of settings", data[2])
+  }
+  z := zone.New(data[0].(string), data[1].(string))
+  for _, r := range data[3:] {
+    z.Add(r.(string))
+  }
+
+  for k, v := range settings {
+    switch k {
+    case "outdoor":
+      z.Outdoor = v.(bool)
+    case "safe":
+      z.Safe = v.(bool)
+    case "reset":
+      z.ResetSecs = v.(float64)
+    case "mood":
+      m := v.([]interface{})
+      if len(m) < 3 {
+        log(dtalog.ERR, "loadZone(%q): mood needs delays and messages", z.Tag)
+        continue
+      }
+      msgs := make([]string, 0, len(m) - 2)
+      for _, x := range m[2:] {
+        msgs = append(msgs, x.(string))
+      }
+      rooms := make([]string, len(z.Rooms))
+      copy(rooms, z.Rooms)
+      mood.NewMessenger(m[0].(float64), m[1].(float64), rooms, msgs)
+    default:
+      log(dtalog.WRN, "loadZone(%q): unknown setting %q", z.Tag, k)
+    }
+  }
+  return nil
+}
+
+// makesThing() returns whether x is a record that makes a thing that a
+// zone reset should make again. (Shops keep their own stock, so they
+// aren't.)
+//
+func makesThing(x []interface{}) bool {
+  cmd := x[0].(string)
+  if _, ok := protoLoaders[cmd]; ok {
+    return true
+  }
+  return (cmd == "inst") || (cmd == "coins")
+}
+
+// subject() returns the reference string of the thing record x is about
+// (if it has one).
+//
+func subject(x []interface{}) string {
+  n := 1
+  if x[0].(string) == "build" {
+    n = 2
+  }
+  if len(x) <= n {
+    return ""
+  }
+  s, _ := x[n].(string)
+  return s
+}
+
+// remakes() returns whether loading x again is part of remaking the things
+// for which keep returns true: x is about one of them, or (for "build"
+// records, which can be about several things, like a key and what it
+// locks) any of its arguments is one of them.
+//
+func remakes(x []interface{}, keep func(string) bool) bool {
+  if x[0].(string) != "build" {
+    return keep(subject(x))
+  }
+  for _, arg := range x[2:] {
+    if r, ok := arg.(string); ok && keep(r) {
+      return true
+    }
+  }
+  return false
+}
+
+// resetZone() puts the things the world file puts in z back where it puts
+// them. The things that start in z are those "pop"ulated into its rooms,
+// and into those things, and so on. Each of them that is still in z (in
+// its rooms, or in other such things there) is destroyed and made again;
+// those that are somewhere else (like in someone's hands) are left alone,
+// as are vehicles (see dta5/vehicle), which go where their routes take them.
+// Whatever else is in a destroyed container is left on the ground.
+//
+func resetZone(z *zone.Zone) {
+  made := make(map[string]bool)
+  for _, x := range worldRecords {
+    if makesThing(x) {
+      made[subject(x)] = true
+    }
+  }
+
+  start := make(map[string]bool)
+  for _, r := range z.Rooms {
+    start[r] = true
+  }
+  for more := true; more; {
+    more = false
+    for _, x := range worldRecords {
+      if (x[0].(string) != "pop") || !start[subject(x)] {
+        continue
+      }
+      for _, t_ref := range x[3:] {
+        if r := t_ref.(string); !start[r] {
+          start[r] = true
+          more = true
+        }
+      }
+    }
+  }
+
+  gone := make(map[string]bool)
+  held := make(map[string]bool)
+  for _, x := range worldRecords {
+    r := subject(x)
+    if !makesThing(x) || !start[r] {
+      continue
+    }
+    t, ok := ref.Deref(r).(thing.Thing)
+    if !ok {
+      continue
+    }
+    if vehicle.Is(t) {
+      gone[r] = true
+    } else if where := zoneRoom(t, z, start); where != nil {
+      evict(t, made, start, where, held)
+      factory.Destroy(t)
+    } else {
+      gone[r] = true
+    }
+  }
+
+  keep := func(r string) bool {
+    return made[r] && start[r] && !gone[r]
+  }
+  for _, x := range worldRecords {
+    cmd := x[0].(string)
+    var err error
+    switch cmd {
+    case "pop":
+      if r := subject(x); !start[r] || gone[r] {
+        continue
+      }
+      rec := []interface{}{ x[1], x[2], }
+      for _, t_ref := range x[3:] {
+        if r := t_ref.(string); keep(r) || held[r] {
+          rec = append(rec, t_ref)
+        }
+      }
+      if len(rec) > 2 {
+        err = populate(rec)
+      }
+    case "door":
+      dwy, ok := ref.Deref(subject(x)).(*door.Doorway)
+      if ok && start[dwy.Ref()] && (len(x) > 3) {
+        dwy.SetOpen(x[3].(bool))
+      }
+    default:
+      if lf, ok := initialLoadMap[cmd]; ok && remakes(x, keep) {
+        err = lf(x[1:])
+        if t, ok := ref.Deref(subject(x)).(desc.Interface); ok && makesThing(x) {
+          desc.Restore(t)
+        }
+      }
+    }
+    if err != nil {
+      log(dtalog.WRN, "resetZone(%q): reloading %v: %s", z.Tag, x, err)
+    }
+  }
+}
+
+// zoneRoom() returns the room of z that t is in, as long as everything
+// between t and the room is also something that starts in z (otherwise
+// nil: t has been carried off, or put in someone else's things).
+//
+func zoneRoom(t thing.Thing, z *zone.Zone, start map[string]bool) *room.Room {
+  for {
+    switch p := t.Loc().Place.(type) {
+    case *room.Room:
+      if z.Has(p.Ref()) {
+        return p
+      }
+      return nil
+    case thing.Thing:
+      if !start[p.Ref()] {
+        return nil
+      }
+      t = p
+    default:
+      return nil
+    }
+  }
+}
+
+// evict() moves everything in t (however deeply) that doesn't start in the
+// zone out onto the ground in r, so destroying t doesn't destroy it.
+// Things that start in the zone but that the world file doesn't make (like
+// door.Doorways, which are permanent) are taken out and noted in held, to
+// be put back when t is made again.
+//
+func evict(t thing.Thing, made, start map[string]bool, r *room.Room, held map[string]bool) {
+  c, ok := t.(thing.Container)
+  if !ok {
+    return
+  }
+  for _, s := range []byte{ thing.IN, thing.ON, thing.BEHIND, thing.UNDER, } {
+    tl := c.Side(s)
+    if tl == nil {
+      continue
+    }
+    inside := make([]thing.Thing, len(tl.Things))
+    copy(inside, tl.Things)
+    for _, x := range inside {
+      switch {
+      case made[x.Ref()] && start[x.Ref()]:
+        evict(x, made, start, r, held)
+      case start[x.Ref()]:
+        tl.Remove(x)
+        held[x.Ref()] = true
+      default:
+        tl.Remove(x)
+        r.Contents.Add(x)
+      }
+    }
+  }
+}
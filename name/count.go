@@ -0,0 +1,165 @@
+// count.go
+//
+// dta5 names for countable things
+//
+// updated 2026-10-19
+//
+// A CountName names a quantity of identical things (like a stack of
+// arrows or a pile of coins) that is treated as a single Thing:
+//
+//  n.Full(0)      => "three copper coins stamped with an owl"
+//  n.Normal(0)    => "three copper coins"
+//  n.Short(0)     => "three coins"
+//  n.Normal(DEF_ART) => "the three copper coins"
+//  n.Normal(NO_ART)  => "copper coins"
+//
+// When the Count is 1, it behaves just like its NormalName ("a copper
+// coin"). It matches either the singular or the plural noun.
+//
+package name
+
+import( "strconv"; "strings"; )
+
+type CountName struct {
+  NormalName
+  Plural string
+  Count  int
+}
+
+var numberWords = []string{ "no", "one", "two", "three", "four", "five",
+  "six", "seven", "eight", "nine", "ten", "eleven", "twelve", "thirteen",
+  "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+  "twenty", }
+
+// NumberWord() returns n spelled out if it's small, or in digits otherwise.
+//
+func NumberWord(n int) string {
+  if (n >= 0) && (n < len(numberWords)) {
+    return numberWords[n]
+  }
+  return strconv.Itoa(n)
+}
+
+// NumberValue() reads a positive number, in either digits or words (as
+// returned by NumberWord()). The boolean is false if s isn't one.
+//
+func NumberValue(s string) (int, bool) {
+  if n, err := strconv.Atoi(s); err == nil {
+    return n, n > 0
+  }
+  s = strings.ToLower(s)
+  for n, w := range numberWords[1:] {
+    if w == s {
+      return n+1, true
+    }
+  }
+  return 0, false
+}
+
+// Pluralize() makes a reasonable guess at the plural of an English noun.
+//
+func Pluralize(noun string) string {
+  l := strings.ToLower(noun)
+  switch {
+  case strings.HasSuffix(l, "s"), strings.HasSuffix(l, "x"),
+       strings.HasSuffix(l, "z"), strings.HasSuffix(l, "ch"),
+       strings.HasSuffix(l, "sh"):
+    return noun + "es"
+  case strings.HasSuffix(l, "y") && (len(l) > 1) &&
+       !strings.ContainsRune("aeiou", rune(l[len(l)-2])):
+    return noun[:len(noun)-1] + "ies"
+  }
+  return noun + "s"
+}
+
+// NewCount() creates a new CountName. artAdjNoun and prep are as for
+// NewNormal(), and describe a single one of the things; if plural is
+// empty, the plural noun is guessed with Pluralize().
+//
+func NewCount(artAdjNoun, plural, prep string, count int) *CountName {
+  nn := NewNormal(artAdjNoun, prep, false)
+  if nn == nil {
+    return nil
+  }
+  if plural == "" {
+    plural = Pluralize(nn.Noun)
+  }
+  return &CountName{ NormalName: *nn, Plural: plural, Count: count, }
+}
+
+// plural() returns an article-less NormalName for the plural.
+//
+func (n CountName) plural() NormalName {
+  return NormalName{ Article: NONE, Noun: n.Plural, Modifiers: n.Modifiers,
+                     PrepPhrase: n.PrepPhrase, genDropsS: true, }
+}
+
+// prefix() returns what goes before the plural name: the article (if
+// requested) and the count.
+//
+func (n CountName) prefix(p NameParam, name string) string {
+  if checkNP(p, NO_ART) {
+    return name
+  }
+  chunks := make([]string, 0, 3)
+  if checkNP(p, DEF_ART) {
+    chunks = append(chunks, "the")
+  }
+  chunks = append(chunks, NumberWord(n.Count), name)
+  return strings.Join(chunks, " ")
+}
+
+// CountName implements the Name interface.
+//
+func (n CountName) Full(p NameParam) string {
+  if n.Count == 1 {
+    return n.NormalName.Full(p)
+  }
+  return n.prefix(p, n.plural().Full(p))
+}
+
+func (n CountName) Normal(p NameParam) string {
+  if n.Count == 1 {
+    return n.NormalName.Normal(p)
+  }
+  return n.prefix(p, n.plural().Normal(p))
+}
+
+func (n CountName) Short(p NameParam) string {
+  if n.Count == 1 {
+    return n.NormalName.Short(p)
+  }
+  return n.prefix(p, n.plural().Short(p))
+}
+
+func (n CountName) Match(toks []string) bool {
+  return n.NormalName.Match(toks) || n.plural().Match(toks)
+}
+
+func (n CountName) SubjPronoun() string {
+  if n.Count == 1 {
+    return "it"
+  }
+  return "they"
+}
+
+func (n CountName) ObjPronoun() string {
+  if n.Count == 1 {
+    return "it"
+  }
+  return "them"
+}
+
+func (n CountName) PossPronoun() string {
+  if n.Count == 1 {
+    return "its"
+  }
+  return "their"
+}
+
+func (n CountName) ReflexPronoun() string {
+  if n.Count == 1 {
+    return "itself"
+  }
+  return "themselves"
+}
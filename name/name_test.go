@@ -196,3 +196,63 @@ func TestProperName(t *testing.T) {
   }
 }
 
+
+var cnames = []*CountName {
+  NewCount("a copper coin", "", "stamped with an owl", 3),
+  NewCount("an iron-tipped arrow", "", "", 1),
+}
+
+var count_match_pairs = []match_pair {
+  { "coin",         cnames[0], true, },
+  { "coins",        cnames[0], true, },
+  { "copper coins", cnames[0], true, },
+  { "cop co",       cnames[0], true, },
+  { "arrows",       cnames[1], true, },
+  { "copper",       cnames[0], false, },
+}
+
+var count_snl_pairs = []short_norm_long_pair {
+  { cnames[0], 0,       "three coins", "three copper coins",
+                        "three copper coins stamped with an owl", },
+  { cnames[0], DEF_ART, "the three coins", "the three copper coins",
+                        "the three copper coins stamped with an owl", },
+  { cnames[0], NO_ART,  "coins", "copper coins",
+                        "copper coins stamped with an owl", },
+  { cnames[1], 0,       "an arrow", "an iron-tipped arrow",
+                        "an iron-tipped arrow", },
+}
+
+func TestCountName(t *testing.T) {
+  for _, p := range count_match_pairs {
+    tokens := strings.Fields(p.input_str)
+    res := p.target.Match(tokens)
+    if res != p.result {
+      t.Errorf("%q Match() %v => %v\nexpected %v\n",
+                p.input_str, p.target, res, p.result)
+    }
+  }
+  
+  for _, p := range count_snl_pairs {
+    s := p.the_name.Short(p.params)
+    n := p.the_name.Normal(p.params)
+    f := p.the_name.Full(p.params)
+    if s != p.short {
+      t.Errorf("Short(%v) => %q\nexpected %q\n", p.params, s, p.short)
+    }
+    if n != p.normal {
+      t.Errorf("Normal(%v) => %q\nexpected %q\n", p.params, n, p.normal)
+    }
+    if f != p.full {
+      t.Errorf("Full(%v) => %q\nexpected %q\n", p.params, f, p.full)
+    }
+  }
+  
+  for _, w := range []string{ "three", "3", "Twenty", "47" } {
+    if _, ok := NumberValue(w); !ok {
+      t.Errorf("NumberValue(%q) failed", w)
+    }
+  }
+  if Pluralize("torch") != "torches" || Pluralize("ruby") != "rubies" {
+    t.Errorf("Pluralize() is wrong")
+  }
+}
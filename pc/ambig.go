@@ -0,0 +1,147 @@
+// ambig.go
+//
+// dta5 PlayerChar disambiguation prompts
+//
+// updated 2026-10-19
+//
+// When a noun phrase without an ordinal matches more than one thing, the
+// parser doesn't just take the first one; it asks which is meant:
+//
+//  > get key
+//  Which "key" do you mean?
+//    1. a brass key in your right hand
+//    2. an iron key on the table
+//  > 2
+//
+// The question is held in the PlayerChar's parser state until the next
+// command. If that command is a number (or an ordinal, like "second") in the
+// range offered, the original command is parsed again with the chosen thing
+// standing in for the ambiguous phrase; otherwise the question is dropped
+// and the new command is parsed as usual. A command with several ambiguous
+// phrases (PUT KEY IN BOX) asks about each in turn; the answers are kept
+// until the command has been carried out (or a question is dropped).
+//
+package pc
+
+import( "fmt"; "strconv"; "strings";
+        "dta5/name"; "dta5/room"; "dta5/thing";
+)
+
+// A choice is a pending disambiguation question.
+//
+type choice struct {
+  cmd    string         // the command that raised the question
+  phrase string         // the ambiguous noun phrase
+  cands  []thing.Thing  // the candidates offered, in order
+}
+
+// choose() returns the candidate selected by the answer toks, or nil if
+// toks isn't an answer to the question.
+//
+func (c *choice) choose(toks []string) thing.Thing {
+  if len(toks) != 1 {
+    return nil
+  }
+  n, err := strconv.Atoi(toks[0])
+  if err != nil {
+    ord, is_ord := ordinals[toks[0]]
+    if !is_ord {
+      return nil
+    }
+    n = ord + 1
+  }
+  if (n < 1) || (n > len(c.cands)) {
+    return nil
+  }
+  return c.cands[n-1]
+}
+
+// pick() resolves the noun phrase toks using find, which should behave like
+// the FindIn...() functions with the given ordinal. If exactly one thing
+// matches, it is returned. If several do, the PlayerChar is asked which is
+// meant and nil is returned, unless the command is being parsed again with
+// an answer to that question, in which case the chosen thing is returned.
+//
+func (pp *PlayerChar) pick(toks []string,
+                           find func(int) (thing.Thing, int)) thing.Thing {
+  cands := make([]thing.Thing, 0, 2)
+  for n := 0; ; n++ {
+    t, _ := find(n)
+    if t == nil {
+      break
+    }
+    cands = append(cands, t)
+  }
+  
+  switch len(cands) {
+  case 0:
+    return nil
+  case 1:
+    return cands[0]
+  }
+  
+  phrase := strings.Join(toks, " ")
+  if a, ok := pp.answers[phrase]; ok {
+    for _, t := range cands {
+      if t == a {
+        return t
+      }
+    }
+  }
+  
+  if pp.question == nil {
+    pp.ask(&choice{ cmd: pp.curCmd, phrase: phrase, cands: cands, })
+  }
+  return nil
+}
+
+// ask() sends the PlayerChar the numbered list of candidates in q and holds
+// q as the PlayerChar's pending question.
+//
+func (pp *PlayerChar) ask(q *choice) {
+  lines := make([]string, 0, len(q.cands) + 1)
+  lines = append(lines, fmt.Sprintf("Which %q do you mean?", q.phrase))
+  for n, t := range q.cands {
+    lines = append(lines, fmt.Sprintf("  %d. %s %s", n+1,
+                                      t.Normal(0), pp.whereIs(t)))
+  }
+  pp.QWrite("%s", strings.Join(lines, "\n"))
+  pp.question = q
+}
+
+// whereIs() returns a short phrase describing where t is, from the
+// PlayerChar's point of view.
+//
+func (pp *PlayerChar) whereIs(t thing.Thing) string {
+  bod := pp.Body()
+  if h, _ := bod.HeldIn("right_hand"); h == t {
+    return "in your right hand"
+  }
+  if h, _ := bod.HeldIn("left_hand"); h == t {
+    return "in your left hand"
+  }
+  
+  loc := t.Loc()
+  switch p := loc.Place.(type) {
+  case *PlayerChar:
+    if p == pp {
+      return "that you are wearing"
+    }
+    return fmt.Sprintf("that %s has", p.Normal(0))
+  case *room.Room:
+    return "here"
+  case thing.Thing:
+    return fmt.Sprintf("%s %s", thing.SideStr(loc.Side), p.Normal(name.DEF_ART))
+  }
+  return ""
+}
+
+// notFound() is for reporting that a noun phrase matched nothing; it says
+// nothing if the phrase was instead ambiguous and a question has just been
+// asked.
+//
+func (pp *PlayerChar) notFound(fmtstr string, args ...interface{}) {
+  if pp.question == nil {
+    pp.QWrite(fmtstr, args...)
+  }
+}
@@ -0,0 +1,228 @@
+// carry.go
+//
+// dta5 PlayerChar carrying capacity, encumbrance, and nested contents
+//
+// updated 2026-10-19
+//
+// How much a PlayerChar can carry, all told (in their hands, on their
+// body, and in and on whatever's there), depends on their body's type (see
+// dta5/body) and on their CarryAttr attribute (see dta5/stats): each point
+// of it above stats.AttrBase adds CarryPerAttr of the mass the body can
+// carry, and each point below takes that much away. How much bulk they can
+// carry, and wear, depends only on the body. These are the limits of the
+// PlayerChar's Inventory (see setLimits()). A Container's mass includes its
+// contents (see dta5/thing), so a backpack full of rocks counts for what
+// it weighs.
+//
+// The more of their CarryLimit() a PlayerChar is carrying, the more
+// encumbered they are (see Encumbrance()); each time an encumbered
+// PlayerChar goes somewhere, they need a moment (see EncumbranceDelays)
+// before they can go on, and an OVERLOADED PlayerChar can't go anywhere.
+//
+package pc
+
+import( "fmt"; "strings";
+        "dta5/act"; "dta5/name"; "dta5/ref"; "dta5/stats"; "dta5/thing";
+)
+
+var CarryAttr string = "str"
+var CarryPerAttr float32 = 0.05
+
+// CarryLimit() returns the most mass the PlayerChar can carry.
+//
+func (pp *PlayerChar) CarryLimit() float32 {
+  bonus := CarryPerAttr * float32(pp.stats.Attr(CarryAttr) - stats.AttrBase)
+  lim := pp.bod.CarryMass() * (1.0 + bonus)
+  if lim < 0.0 {
+    return 0.0
+  }
+  return lim
+}
+
+// Carried() returns the total mass of everything the PlayerChar has.
+//
+func (pp *PlayerChar) Carried() float32 {
+  return pp.Inventory.TotalMass().Value
+}
+
+// setLimits() brings the mass and bulk limits of the PlayerChar's
+// Inventory up to date with their body and CarryAttr.
+//
+func (pp *PlayerChar) setLimits() {
+  pp.Inventory.MassLimit = thing.TVal{ VT: thing.VT_LTD, Value: pp.CarryLimit(), }
+  pp.Inventory.BulkLimit = thing.TVal{ VT: thing.VT_LTD, Value: pp.bod.CarryBulk(), }
+}
+
+// carries() returns whether t is (however deeply) among the PlayerChar's
+// things.
+//
+func (pp *PlayerChar) carries(t thing.Thing) bool {
+  for p := t.Loc().Place; p != nil; {
+    if p == ref.Interface(pp) {
+      return true
+    }
+    pt, ok := p.(thing.Thing)
+    if !ok {
+      return false
+    }
+    p = pt.Loc().Place
+  }
+  return false
+}
+
+// canCarry() returns whether the PlayerChar can take t (which they don't
+// already have) without going over what they can carry.
+//
+func (pp *PlayerChar) canCarry(t thing.Thing) bool {
+  return pp.canSwap(nil, []thing.Thing{ t, })
+}
+
+// canSwap() returns whether the PlayerChar could carry everything in take
+// after giving up everything in give, without going over the mass and bulk
+// they can carry.
+//
+func (pp *PlayerChar) canSwap(give, take []thing.Thing) bool {
+  pp.setLimits()
+  kept := make([]thing.Thing, 0, len(pp.Inventory.Things))
+  for _, t := range pp.Inventory.Things {
+    given := false
+    for _, g := range give {
+      given = given || (g == t)
+    }
+    if !given {
+      kept = append(kept, t)
+    }
+  }
+  tl := &thing.ThingList{ Things: kept, MassLimit: pp.Inventory.MassLimit,
+                          BulkLimit: pp.Inventory.BulkLimit, }
+  for _, t := range take {
+    if !tl.WillFitMass(t) || !tl.WillFitBulk(t) {
+      return false
+    }
+    tl.Things = append(tl.Things, t)
+  }
+  return true
+}
+
+// canWear() returns whether the PlayerChar can wear t (which they're
+// holding) on top of everything else they're wearing.
+//
+func (pp *PlayerChar) canWear(t thing.Thing) bool {
+  worn := &thing.ThingList{ Things: make([]thing.Thing, 0, len(pp.Inventory.Things)),
+                            MassLimit: thing.INFTY,
+                            BulkLimit: thing.TVal{ VT: thing.VT_LTD, Value: pp.bod.WornBulk(), }, }
+  for _, x := range pp.Inventory.Things {
+    if !pp.bod.IsHolding(x) {
+      worn.Things = append(worn.Things, x)
+    }
+  }
+  return worn.WillFitMass(t) && worn.WillFitBulk(t)
+}
+
+type Encumbrance byte
+
+const(  UNENCUMBERED Encumbrance = iota
+        BURDENED
+        ENCUMBERED
+        OVERLOADED
+)
+
+var encumbranceStrs = map[Encumbrance]string {
+  UNENCUMBERED: "You are unencumbered.",
+  BURDENED:     "You are somewhat burdened by what you're carrying.",
+  ENCUMBERED:   "You are heavily burdened by what you're carrying.",
+  OVERLOADED:   "You are carrying too much to go anywhere.",
+}
+
+// The fraction of their CarryLimit() a PlayerChar must be carrying to be
+// BURDENED and ENCUMBERED (more than all of it is OVERLOADED), and how many
+// seconds they need after going somewhere, at each level, before they can
+// go on.
+//
+var EncumbranceLevels = map[Encumbrance]float32 {
+  BURDENED:   0.5,
+  ENCUMBERED: 0.75,
+}
+var EncumbranceDelays = map[Encumbrance]float64 {
+  BURDENED:   1.0,
+  ENCUMBERED: 3.0,
+}
+
+// Encumbrance() returns how encumbered the PlayerChar is.
+//
+func (pp *PlayerChar) Encumbrance() Encumbrance {
+  lim, m := pp.CarryLimit(), pp.Carried()
+  switch {
+  case m > lim:
+    return OVERLOADED
+  case m >= lim * EncumbranceLevels[ENCUMBERED]:
+    return ENCUMBERED
+  case m >= lim * EncumbranceLevels[BURDENED]:
+    return BURDENED
+  }
+  return UNENCUMBERED
+}
+
+// moveDelay() returns how long the PlayerChar needs after going somewhere
+// before they can go on.
+//
+func (pp *PlayerChar) moveDelay() float64 {
+  return EncumbranceDelays[pp.Encumbrance()]
+}
+
+// canMove() returns whether the PlayerChar is unburdened enough (and
+// rested enough) to go somewhere now, and tells them if not.
+//
+func (pp *PlayerChar) canMove() bool {
+  if pp.Encumbrance() == OVERLOADED {
+    pp.QWrite("%s", encumbranceStrs[OVERLOADED])
+    return false
+  }
+  if pp.winded {
+    pp.QWrite("You need a moment to shift your load before you go on.")
+    return false
+  }
+  return true
+}
+
+// moved() is called whenever the PlayerChar goes somewhere, and keeps them
+// from going on until they've had the moment they need.
+//
+func (pp *PlayerChar) moved() {
+  if d := pp.moveDelay(); d > 0.0 {
+    pp.winded = true
+    act.Add(d, func() error {
+      pp.winded = false
+      return nil
+    })
+  }
+}
+
+// nestedLines() describes what the PlayerChar can see in and on t, and (one
+// level further indented each time) in and on those things, and so on, one
+// line for each side of each Container that has anything there. Things in
+// closed Containers can't be seen.
+//
+func (pp *PlayerChar) nestedLines(t thing.Thing, depth int) []string {
+  lines := make([]string, 0, 0)
+  c, ok := t.(thing.Container)
+  if !ok {
+    return lines
+  }
+  for _, s := range []byte{ thing.IN, thing.ON, } {
+    tl := c.Side(s)
+    if (tl == nil) || ((s == thing.IN) && !c.IsOpen()) {
+      continue
+    }
+    tl = pp.visible(tl)
+    if len(tl.Things) == 0 {
+      continue
+    }
+    lines = append(lines, fmt.Sprintf("%s%s %s: %s", strings.Repeat(".   ", depth),
+                                      thing.SideStr(s), t.Normal(name.DEF_ART), tl.EnglishList()))
+    for _, x := range tl.Things {
+      lines = append(lines, pp.nestedLines(x, depth + 1)...)
+    }
+  }
+  return lines
+}
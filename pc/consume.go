@@ -0,0 +1,71 @@
+// consume.go
+//
+// dta5 PlayerChar eat/drink/use verbs
+//
+// updated 2026-10-19
+//
+// EAT, DRINK, and USE consume a portion of a held thing.Consumable (if
+// it's the right verb for it) and apply its effects (see dta5/effect).
+// When the last portion is gone, the thing is too.
+//
+package pc
+
+import(
+        "dta5/effect"; "dta5/factory"; "dta5/msg"; "dta5/name"; "dta5/room";
+        "dta5/thing"; "dta5/util";
+)
+
+// type DoFunc func(*PlayerChar,
+//                  string,           verb
+//                  thing.Thing,      direct object
+//                  string,           preposition
+//                  thing.Thing,      indirect object
+//                  string)           complete command text
+
+func DoConsume(pp *PlayerChar, verb string, dobj thing.Thing,
+               prep string, iobj thing.Thing, text string) {
+
+  if dobj == nil {
+    pp.QWrite("%s what?", util.Cap(verb))
+    return
+  }
+
+  c, ok := dobj.(*thing.Consumable)
+  if !ok || (c.How() != verb) {
+    pp.QWrite("You cannot %s %s.", verb, dobj.Normal(name.DEF_ART))
+    return
+  }
+  if !pp.Body().IsHolding(dobj) {
+    pp.QWrite("You must be holding %s to %s it.", dobj.Normal(name.DEF_ART), verb)
+    return
+  }
+
+  loc := pp.where.Place.(*room.Room)
+  sname := util.Cap(pp.nameIn(loc))
+  var m *msg.Message
+  switch verb {
+  case thing.EAT:
+    m = msg.New("txt", "%s eats some of %s.", sname, dobj.Normal(0))
+    m.Add(pp, "txt", "You eat some of %s.", dobj.Normal(0))
+  case thing.DRINK:
+    m = msg.New("txt", "%s drinks from %s.", sname, dobj.Normal(0))
+    m.Add(pp, "txt", "You drink from %s.", dobj.Normal(0))
+  default:
+    m = msg.New("txt", "%s uses %s.", sname, dobj.Normal(0))
+    m.Add(pp, "txt", "You use %s.", dobj.Normal(0))
+  }
+  loc.Deliver(m)
+  effect.Apply(pp, c, c.Effects())
+
+  if c.Consume() == 0 {
+    factory.Destroy(c)
+    switch verb {
+    case thing.EAT:
+      pp.QWrite("You finish %s.", c.Normal(name.DEF_ART))
+    case thing.DRINK:
+      pp.QWrite("You drink the last of %s.", c.Normal(name.DEF_ART))
+    default:
+      pp.QWrite("%s is all used up.", util.Cap(c.Normal(name.DEF_ART)))
+    }
+  }
+}
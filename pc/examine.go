@@ -2,7 +2,7 @@
 //
 // dta5 PlayerChar verb for looking more closely at an object.
 //
-// updated 2017-08-15
+// updated 2026-10-19
 //
 package pc
 
@@ -26,6 +26,11 @@ func DoExamine(pp *PlayerChar, verb string, dobj thing.Thing,
     return
   }
   
+  if pp.inDark() {
+    pp.QWrite("It is too dark to examine anything.")
+    return
+  }
+  
   bod := pp.Body()
   if !bod.IsHolding(dobj) {
     pp.QWrite("You must be holding %s to examine it.", dobj.Normal(name.DEF_ART))
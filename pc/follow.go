@@ -0,0 +1,246 @@
+// follow.go
+//
+// dta5 PlayerChar following and groups
+//
+// updated 2026-10-19
+//
+// FOLLOW <pc> joins the group that <pc> leads (or is in), and whenever the
+// group's leader goes somewhere, everyone in the group who was with them
+// goes the same way, under their own steam (so they get their own leaving
+// and arriving messages, and can be stopped by the same things: a door
+// that's closed by the time they get to it, not standing up, a script).
+// Anyone who can't keep up drops out of the group, as does anyone who goes
+// off on their own, or logs out. When only the leader is left, the group
+// breaks up.
+//
+package pc
+
+import( "strings";
+        "github.com/delicb/gstring";
+        "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/thing"; "dta5/util";
+)
+
+// A group is led by its first member.
+//
+type group struct {
+  members []*PlayerChar
+}
+
+func (g *group) leader() *PlayerChar { return g.members[0] }
+
+// names() returns the names of the group's followers other than pp.
+//
+func (g *group) names(pp *PlayerChar) []string {
+  names := make([]string, 0, len(g.members))
+  for _, m := range g.members[1:] {
+    if m != pp {
+      names = append(names, m.Normal(0))
+    }
+  }
+  return names
+}
+
+// leaveGroup() takes the PlayerChar out of their group (if any), telling
+// the others what they did (how: "leaves the group"); if the PlayerChar led
+// it, or only its leader is left, it breaks up.
+//
+func (pp *PlayerChar) leaveGroup(how string) {
+  g := pp.group
+  if g == nil {
+    return
+  }
+  pp.group = nil
+  sname := util.Cap(pp.Normal(0))
+
+  if g.leader() == pp {
+    for _, m := range g.members[1:] {
+      m.group = nil
+      m.QWrite("%s breaks up the group, and you stop following %s.", sname, pp.ObjPronoun())
+    }
+    g.members = nil
+    return
+  }
+
+  for n, m := range g.members {
+    if m == pp {
+      g.members = append(g.members[:n], g.members[n+1:]...)
+      break
+    }
+  }
+  for _, m := range g.members {
+    m.QWrite("%s %s.", sname, how)
+  }
+  if len(g.members) == 1 {
+    g.leader().group = nil
+  }
+}
+
+// wanderOff() is called when the PlayerChar goes somewhere on their own; if
+// they were following someone, they stop.
+//
+func (pp *PlayerChar) wanderOff() {
+  if (pp.group == nil) || (pp.group.leader() == pp) {
+    return
+  }
+  pp.QWrite("You stop following %s.", pp.group.leader().Normal(0))
+  pp.leaveGroup("goes off on " + pp.PossPronoun() + " own")
+}
+
+// lead() takes along everyone in the PlayerChar's group who was with them
+// in from, if they lead one, by having each of them go the same way; those
+// who don't end up in to drop out.
+//
+func (pp *PlayerChar) lead(from, to *room.Room, way func(*PlayerChar)) {
+  g := pp.group
+  if (g == nil) || (g.leader() != pp) || (way == nil) {
+    return
+  }
+  followers := make([]*PlayerChar, len(g.members) - 1)
+  copy(followers, g.members[1:])
+  for _, f := range followers {
+    if (f.group != g) || (f.where.Place != from) {
+      continue
+    }
+    f.stopTrip()
+    f.tagAlong = true
+    way(f)
+    f.tagAlong = false
+    if (f.group == g) && (f.where.Place != to) {
+      f.QWrite("You can't keep up with %s, and stop following %s.", pp.Normal(0), pp.ObjPronoun())
+      f.leaveGroup("falls behind")
+    }
+  }
+}
+
+// type DoFunc func(*PlayerChar,
+//                  string,           verb
+//                  thing.Thing,      direct object
+//                  string,           preposition
+//                  thing.Thing,      indirect object
+//                  string)           complete command text
+
+func DoFollow(pp *PlayerChar, verb string, dobj thing.Thing,
+              prep string, iobj thing.Thing, text string) {
+  if dobj == nil {
+    DoGroup(pp, "group", nil, "", nil, text)
+    return
+  }
+  tp, ok := dobj.(*PlayerChar)
+  if !ok {
+    pp.QWrite("You can't follow %s.", dobj.Normal(name.DEF_ART))
+    return
+  }
+  if tp == pp {
+    pp.QWrite("You can't follow yourself.")
+    return
+  }
+  leader := tp
+  if tp.group != nil {
+    leader = tp.group.leader()
+  }
+  if leader == pp {
+    pp.QWrite("%s is already following you.", util.Cap(tp.Normal(0)))
+    return
+  }
+  if (pp.group != nil) && (pp.group == leader.group) {
+    pp.QWrite("You are already following %s.", leader.Normal(0))
+    return
+  }
+
+  if pp.group != nil {
+    if pp.group.leader() == pp {
+      pp.QWrite("You stop leading your group.")
+    } else {
+      pp.QWrite("You stop following %s.", pp.group.leader().Normal(0))
+    }
+    pp.leaveGroup("leaves the group")
+  }
+
+  g := leader.group
+  if g == nil {
+    g = &group{ members: []*PlayerChar{ leader, }, }
+    leader.group = g
+  }
+  loc := pp.where.Place.(*room.Room)
+  sname, lname := util.Cap(pp.Normal(0)), leader.Normal(0)
+  for _, m := range g.members {
+    if m.where.Place != pp.where.Place {
+      m.QWrite("%s joins your group.", sname)
+    }
+  }
+  g.members = append(g.members, pp)
+  pp.group = g
+
+  m := msg.New("txt", "%s starts following %s.", sname, lname)
+  m.Add(pp, "txt", "You start following %s.", lname)
+  m.Add(leader, "txt", "%s starts following you.", sname)
+  loc.Deliver(m)
+}
+
+func DoUnfollow(pp *PlayerChar, verb string, dobj thing.Thing,
+                prep string, iobj thing.Thing, text string) {
+  g := pp.group
+  switch {
+  case g == nil:
+    pp.QWrite("You aren't following anyone.")
+  case g.leader() == pp:
+    pp.QWrite("You stop leading your group.")
+    pp.leaveGroup("")
+  default:
+    pp.QWrite("You stop following %s.", g.leader().Normal(0))
+    pp.leaveGroup("leaves the group")
+  }
+}
+
+func DoGroup(pp *PlayerChar, verb string, dobj thing.Thing,
+             prep string, iobj thing.Thing, text string) {
+  g := pp.group
+  switch {
+  case g == nil:
+    pp.QWrite("You aren't following anyone, and no one is following you.")
+  case g.leader() == pp:
+    pp.QWrite("You are leading a group, followed by %s.", util.EnglishList(g.names(pp)))
+  case len(g.members) == 2:
+    pp.QWrite("You are following %s.", g.leader().Normal(0))
+  default:
+    pp.QWrite("You are following %s, along with %s.",
+              g.leader().Normal(0), util.EnglishList(g.names(pp)))
+  }
+}
+
+// DoGroupSay() says something to everyone in the PlayerChar's group,
+// wherever they are.
+//
+func DoGroupSay(pp *PlayerChar, verb string, dobj thing.Thing,
+                prep string, iobj thing.Thing, text string) {
+  g := pp.group
+  if g == nil {
+    pp.QWrite("You aren't in a group.")
+    return
+  }
+  words := strings.SplitN(strings.TrimSpace(text), " ", 2)
+  if (len(words) < 2) || (strings.TrimSpace(words[1]) == "") {
+    pp.QWrite("Say what to your group?")
+    return
+  }
+  said := util.Cap(strings.TrimSpace(words[1]))
+
+  var f1p = map[string]interface{} { "subj": "You", "verb": "say", "text": said, "punct": "", }
+  var f3p = map[string]interface{} { "subj": util.Cap(pp.Normal(0)), "verb": "says", "text": said, "punct": "", }
+  switch said[len(said)-1] {
+  case '.':
+  case '!':
+    f1p["verb"], f3p["verb"] = "exclaim", "exclaims"
+  case '?':
+    f1p["verb"], f3p["verb"] = "ask", "asks"
+  default:
+    f1p["punct"], f3p["punct"] = ".", "."
+  }
+  for _, m := range g.members {
+    f := f3p
+    if m == pp {
+      f = f1p
+    }
+    m.Send(msg.Env{ Type: "speech", Text: "[group] " + gstring.Sprintm(sayTemplate, f), })
+  }
+}
@@ -2,13 +2,13 @@
 //
 // dta5 PlayerChar get, put verbs
 //
-// updated 2017-08-18
+// updated 2026-10-19
 //
 package pc
 
 import(
         "dta5/log";
-        "dta5/msg"; "dta5/name"; "dta5/ref"; "dta5/room";
+        "dta5/factory"; "dta5/msg"; "dta5/name"; "dta5/ref"; "dta5/room";
         "dta5/thing"; "dta5/util";
 )
 
@@ -27,10 +27,23 @@ func DoGet(pp *PlayerChar, verb string,
       ref.NilGuard(pp), verb, ref.NilGuard(dobj), prep,
       ref.NilGuard(iobj), text)
   
+  if s, is_stack := dobj.(*thing.Stack); is_stack && (pp.qty > s.Count()) {
+    pp.QWrite("There %s only %s.", verbBe(s), s.Normal(name.DEF_ART))
+    return
+  }
+  
+  if c, is_coins := thing.IsCoins(dobj); is_coins {
+    if part := c.Split(pp.qty); part != nil {
+      c = part
+    }
+    pp.pocket(c, prep, iobj)
+    return
+  }
+  
   bod := pp.Body()
   rh, _ := bod.HeldIn("right_hand")
   lh, _ := bod.HeldIn("left_hand")
-  if (rh != nil) && (lh != nil) {
+  if (rh != nil) && (lh != nil) && (pp.heldStack(dobj) == nil) {
     pp.QWrite("You don't have a free hand to pick anything up.")
     return
   }
@@ -49,6 +62,27 @@ func DoGet(pp *PlayerChar, verb string,
     return
   }
   
+  whole := dobj
+  if s, is_stack := dobj.(*thing.Stack); is_stack {
+    if part := s.Split(pp.qty); part != nil {
+      dobj = part
+    }
+  }
+  
+  if !pp.carries(whole) && !pp.canCarry(dobj) {
+    pp.QWrite("You can't carry %s as well as everything else you have.",
+              dobj.Normal(name.DEF_ART))
+    if dobj != whole {
+      whole.(*thing.Stack).Merge(dobj.(*thing.Stack))
+    }
+    return
+  }
+  
+  // Once it's been picked up, it isn't hidden anymore (see search.go).
+  if c, ok := dobj.(thing.Concealable); ok {
+    c.SetConcealment(0)
+  }
+  
   var revealed = make([]string, 0, 0)
   
   if iobj == nil {
@@ -116,6 +150,11 @@ func DoGet(pp *PlayerChar, verb string,
     }
   }
   
+  if hs := pp.heldStack(dobj); hs != nil {
+    hs.Merge(dobj.(*thing.Stack))
+    return
+  }
+  
   if rh, _ := bod.HeldIn("right_hand"); rh == nil {
     bod.SetHeld("right_hand", dobj)
   } else {
@@ -125,6 +164,31 @@ func DoGet(pp *PlayerChar, verb string,
   pp.Inventory.Add(dobj)
 }
 
+// heldStack() returns the thing.Stack in the PlayerChar's hands that t
+// (if it's a Stack) would merge into, or nil if there isn't one.
+//
+func (pp *PlayerChar) heldStack(t thing.Thing) *thing.Stack {
+  s, is_stack := t.(*thing.Stack)
+  if !is_stack {
+    return nil
+  }
+  for _, h := range pp.held() {
+    if hs, ok := h.(*thing.Stack); ok && (hs != s) && (hs.Kind() == s.Kind()) {
+      return hs
+    }
+  }
+  return nil
+}
+
+// verbBe() returns "is" or "are" to agree with t.
+//
+func verbBe(t thing.Thing) string {
+  if t.SubjPronoun() == "they" {
+    return "are"
+  }
+  return "is"
+}
+
 
 func DoPut(pp *PlayerChar, verb string,
            dobj thing.Thing, prep string, iobj thing.Thing,
@@ -149,6 +213,25 @@ func DoPut(pp *PlayerChar, verb string,
     return
   }
   
+  // When putting down only some of a held Stack, the rest stays in hand;
+  // if the put fails, the part split off gets merged back.
+  var whole *thing.Stack = nil
+  var done bool = false
+  if s, is_stack := dobj.(*thing.Stack); is_stack && (pp.qty > 0) {
+    if pp.qty > s.Count() {
+      pp.QWrite("You are only holding %s.", s.Normal(0))
+      return
+    }
+    if part := s.Split(pp.qty); part != nil {
+      whole, dobj = s, part
+      defer func() {
+        if !done {
+          whole.Merge(part)
+        }
+      }()
+    }
+  }
+  
   if iobj != nil {
     switch t_iobj := iobj.(type) {
     case thing.Container:
@@ -176,17 +259,22 @@ func DoPut(pp *PlayerChar, verb string,
         }
       }
       
-      if rh, _ := bod.HeldIn("right_hand"); dobj == rh {
-        bod.SetHeld("right_hand", nil)
-      } else {
-        bod.SetHeld("left_hand", nil)
+      if whole == nil {
+        if rh, _ := bod.HeldIn("right_hand"); dobj == rh {
+          bod.SetHeld("right_hand", nil)
+        } else {
+          bod.SetHeld("left_hand", nil)
+        }
+        pp.Inventory.Remove(dobj)
       }
-      pp.Inventory.Remove(dobj)
-      sid.Add(dobj)
-      
+      // The message goes together first, because a Stack may get merged
+      // into one already there (and so be left empty) by Add().
       put_msg := msg.New("txt", "%s puts %s %s %s.", util.Cap(pp.Normal(0)),
                           dobj.Normal(0), prep, iobj.Normal(0))
       put_msg.Add(pp, "txt", "You put %s %s %s.", dobj.Normal(0), prep, iobj.Normal(0))
+      sid.Add(dobj)
+      done = true
+      
       pp.where.Place.(*room.Room).Deliver(put_msg)
       return
     default:
@@ -196,17 +284,39 @@ func DoPut(pp *PlayerChar, verb string,
   } else {
     rm := pp.where.Place.(*room.Room)
   
-    if rh, _ := bod.HeldIn("right_hand"); rh == dobj {
-      bod.SetHeld("right_hand", nil)
-    } else {
-      bod.SetHeld("left_hand", nil)
+    if whole == nil {
+      if rh, _ := bod.HeldIn("right_hand"); rh == dobj {
+        bod.SetHeld("right_hand", nil)
+      } else {
+        bod.SetHeld("left_hand", nil)
+      }
+      pp.Inventory.Remove(dobj)
     }
-    pp.Inventory.Remove(dobj)
-    rm.Contents.Add(dobj)
-    
     put_msg := msg.New("txt", "%s drops %s.", util.Cap(pp.Normal(0)), dobj.Normal(0))
     put_msg.Add(pp, "txt", "You drop %s.", dobj.Normal(0))
+    rm.Contents.Add(dobj)
+    done = true
+    
     rm.Deliver(put_msg)
     return
   }
 }
+
+// pocket() puts a pile of coins in the PlayerChar's purse (which means the
+// pile itself ceases to exist).
+//
+func (pp *PlayerChar) pocket(c *thing.Stack, prep string, iobj thing.Thing) {
+  factory.Destroy(c)
+  pp.coins += c.Count()
+  
+  var mesg *msg.Message
+  if iobj == nil {
+    mesg = msg.New("txt", "%s picks up %s.", util.Cap(pp.Normal(0)), c.Normal(0))
+    mesg.Add(pp, "txt", "You pick up %s.", c.Normal(0))
+  } else {
+    mesg = msg.New("txt", "%s gets %s from %s %s.", util.Cap(pp.Normal(0)),
+                   c.Normal(0), prep, iobj.Normal(0))
+    mesg.Add(pp, "txt", "You get %s from %s %s.", c.Normal(0), prep, iobj.Normal(0))
+  }
+  pp.where.Place.(*room.Room).Deliver(mesg)
+}
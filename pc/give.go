@@ -0,0 +1,536 @@
+// give.go
+//
+// dta5 PlayerChar verbs for handing things to other characters
+//
+// updated 2026-10-19
+//
+// Nothing is ever forced into a character's hands. GIVE makes an offer,
+// which the recipient can ACCEPT or DECLINE; an offer not answered within
+// OfferTimeout seconds is withdrawn. SHOW just lets the other character
+// have a look at something without it changing hands.
+//
+// TRADE opens a two-sided "window" between characters, into which each can
+// OFFER things held in hand. When both AGREE to the current contents of the
+// window, everything on both sides changes hands at once (or, if that isn't
+// possible, nothing does). Any change to the window withdraws both parties'
+// agreement.
+//
+package pc
+
+import( "strings";
+        "dta5/act"; "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/scripts";
+        "dta5/thing"; "dta5/util";
+)
+
+// OfferTimeout is the number of seconds a GIVE offer (or a TRADE proposal)
+// stands before being withdrawn.
+//
+var OfferTimeout float64 = 30.0
+
+// An offer is a standing GIVE (or TRADE proposal, in which case item is nil)
+// from one PlayerChar to another.
+//
+type offer struct {
+  from *PlayerChar
+  to   *PlayerChar
+  item thing.Thing
+}
+
+// A trade is a TRADE window open between two PlayerChars.
+//
+type trade struct {
+  who    [2]*PlayerChar
+  items  [2][]thing.Thing
+  agreed [2]bool
+}
+
+// side() returns the index in the trade's arrays of the given PlayerChar.
+//
+func (tr *trade) side(pp *PlayerChar) int {
+  if tr.who[0] == pp {
+    return 0
+  }
+  return 1
+}
+
+// withdrawAgreement() is called whenever the contents of the trade change.
+//
+func (tr *trade) withdrawAgreement() {
+  tr.agreed[0], tr.agreed[1] = false, false
+}
+
+// close() closes the trade window for both parties.
+//
+func (tr *trade) close() {
+  for _, p := range tr.who {
+    if p.trade == tr {
+      p.trade = nil
+    }
+  }
+}
+
+// describe() lists the contents of the trade window from the point of view
+// of pp.
+//
+func (tr *trade) describe(pp *PlayerChar) string {
+  me := tr.side(pp)
+  them := 1 - me
+  
+  list := func(stuff []thing.Thing) string {
+    if len(stuff) == 0 {
+      return "nothing"
+    }
+    names := make([]string, 0, len(stuff))
+    for _, t := range stuff {
+      names = append(names, t.Normal(0))
+    }
+    return util.EnglishList(names)
+  }
+  
+  lines := []string{
+    "You are trading with " + tr.who[them].Normal(0) + ".",
+    "  You offer " + list(tr.items[me]) + ".",
+    "  " + util.Cap(tr.who[them].SubjPronoun()) + " offers " +
+        list(tr.items[them]) + ".",
+  }
+  if tr.agreed[me] {
+    lines = append(lines, "You have agreed to this trade.")
+  }
+  if tr.agreed[them] {
+    lines = append(lines, util.Cap(tr.who[them].SubjPronoun()) +
+                          " has agreed to this trade.")
+  }
+  return strings.Join(lines, "\n")
+}
+
+// type DoFunc func(*PlayerChar,
+//                  string,           verb
+//                  thing.Thing,      direct object
+//                  string,           preposition
+//                  thing.Thing,      indirect object
+//                  string)           complete command text
+
+// checkRecipient() ensures iobj is another PlayerChar present in the room,
+// complaining and returning nil otherwise.
+//
+func (pp *PlayerChar) checkRecipient(verb string, dobj,
+                                     iobj thing.Thing) *PlayerChar {
+  if dobj == nil {
+    pp.QWrite("%s what?", util.Cap(verb))
+    return nil
+  }
+  if iobj == nil {
+    pp.QWrite("%s %s to whom?", util.Cap(verb), dobj.Normal(name.DEF_ART))
+    return nil
+  }
+  tgt, is_pc := iobj.(*PlayerChar)
+  if !is_pc {
+    pp.QWrite("You can only %s things to other people.", verb)
+    return nil
+  }
+  if tgt == pp {
+    pp.QWrite("You can't %s anything to yourself.", verb)
+    return nil
+  }
+  if tgt.where.Place != pp.where.Place {
+    pp.QWrite("%s isn't here.", util.Cap(tgt.Normal(0)))
+    return nil
+  }
+  if !pp.Body().IsHolding(dobj) {
+    pp.QWrite("You must be holding %s to %s it.", dobj.Normal(name.DEF_ART), verb)
+    return nil
+  }
+  return tgt
+}
+
+func DoGive(pp *PlayerChar, verb string, dobj thing.Thing,
+            prep string, iobj thing.Thing, text string) {
+  
+  tgt := pp.checkRecipient(verb, dobj, iobj)
+  if tgt == nil {
+    return
+  }
+  if tgt.offer != nil {
+    pp.QWrite("%s is already considering an offer.", util.Cap(tgt.Normal(0)))
+    return
+  }
+  
+  o := &offer{ from: pp, to: tgt, item: dobj, }
+  tgt.offer = o
+  
+  m := msg.New("txt", "%s offers %s to %s.", util.Cap(pp.Normal(0)),
+               dobj.Normal(0), tgt.Normal(0))
+  m.Add(pp, "txt", "You offer %s to %s.", dobj.Normal(0), tgt.Normal(0))
+  m.Add(tgt, "txt", "%s offers you %s. (ACCEPT or DECLINE it.)",
+        util.Cap(pp.Normal(0)), dobj.Normal(0))
+  pp.where.Place.(*room.Room).Deliver(m)
+  
+  act.Add(OfferTimeout, func() error {
+    if tgt.offer == o {
+      tgt.offer = nil
+      pp.QWrite("%s has not accepted your offer of %s.",
+                util.Cap(tgt.Normal(0)), dobj.Normal(name.DEF_ART))
+      tgt.QWrite("%s withdraws %s offer of %s.", util.Cap(pp.Normal(0)),
+                 pp.PossPronoun(), dobj.Normal(name.DEF_ART))
+    }
+    return nil
+  })
+}
+
+func DoShow(pp *PlayerChar, verb string, dobj thing.Thing,
+            prep string, iobj thing.Thing, text string) {
+  
+  tgt := pp.checkRecipient(verb, dobj, iobj)
+  if tgt == nil {
+    return
+  }
+  
+  m := msg.New("txt", "%s shows %s to %s.", util.Cap(pp.Normal(0)),
+               dobj.Normal(0), tgt.Normal(0))
+  m.Add(pp, "txt", "You show %s to %s.", dobj.Normal(0), tgt.Normal(0))
+  m.Add(tgt, "txt", "%s shows you %s %s.", util.Cap(pp.Normal(0)),
+        pp.PossPronoun(), dobj.Normal(name.NO_ART))
+  pp.where.Place.(*room.Room).Deliver(m)
+  
+  tgt.QWrite("You see %s.", dobj.Full(0))
+  tgt.QWrite("%s", dobj.Desc())
+}
+
+// freeHands() returns the number of the PlayerChar's empty hands.
+//
+func (pp *PlayerChar) freeHands() int {
+  return 2 - len(pp.held())
+}
+
+// Release() takes a held thing out of the PlayerChar's hands and inventory.
+//
+func (pp *PlayerChar) Release(t thing.Thing) {
+  bod := pp.Body()
+  for _, slot := range []string{"right_hand", "left_hand"} {
+    if h, _ := bod.HeldIn(slot); h == t {
+      bod.SetHeld(slot, nil)
+    }
+  }
+  pp.Inventory.Remove(t)
+}
+
+// receive() puts a thing in one of the PlayerChar's empty hands; there
+// must be one.
+//
+func (pp *PlayerChar) receive(t thing.Thing) {
+  bod := pp.Body()
+  if rh, _ := bod.HeldIn("right_hand"); rh == nil {
+    bod.SetHeld("right_hand", t)
+  } else {
+    bod.SetHeld("left_hand", t)
+  }
+  pp.Inventory.Add(t)
+}
+
+func DoAccept(pp *PlayerChar, verb string, dobj thing.Thing,
+              prep string, iobj thing.Thing, text string) {
+  
+  o := pp.offer
+  if o == nil {
+    pp.QWrite("No one is offering you anything.")
+    return
+  }
+  pp.offer = nil
+  giver := o.from
+  
+  if (PlayerChars[giver.ref] != giver) || (giver.where.Place != pp.where.Place) {
+    pp.QWrite("%s is no longer here.", util.Cap(giver.Normal(0)))
+    return
+  }
+  if !giver.Body().IsHolding(o.item) {
+    pp.QWrite("%s is no longer holding %s.", util.Cap(giver.Normal(0)),
+              o.item.Normal(name.DEF_ART))
+    return
+  }
+  if pp.freeHands() < 1 {
+    pp.offer = o
+    pp.QWrite("You don't have a free hand to accept %s.",
+              o.item.Normal(name.DEF_ART))
+    return
+  }
+  if !pp.canCarry(o.item) {
+    pp.offer = o
+    pp.QWrite("You can't carry %s as well as everything else you have.",
+              o.item.Normal(name.DEF_ART))
+    return
+  }
+  if !scripts.Check(pp, o.item, giver, "accept", "from", text) {
+    return
+  }
+  
+  giver.Release(o.item)
+  pp.receive(o.item)
+  
+  m := msg.New("txt", "%s accepts %s from %s.", util.Cap(pp.Normal(0)),
+               o.item.Normal(0), giver.Normal(0))
+  m.Add(pp, "txt", "You accept %s from %s.", o.item.Normal(0), giver.Normal(0))
+  m.Add(giver, "txt", "%s accepts %s.", util.Cap(pp.Normal(0)),
+        o.item.Normal(name.DEF_ART))
+  pp.where.Place.(*room.Room).Deliver(m)
+  pp.remember(o.item)
+}
+
+func DoDecline(pp *PlayerChar, verb string, dobj thing.Thing,
+               prep string, iobj thing.Thing, text string) {
+  
+  o := pp.offer
+  if o == nil {
+    pp.QWrite("No one is offering you anything.")
+    return
+  }
+  pp.offer = nil
+  
+  pp.QWrite("You decline %s's offer of %s.", o.from.Normal(0),
+            o.item.Normal(name.DEF_ART))
+  o.from.QWrite("%s declines your offer of %s.", util.Cap(pp.Normal(0)),
+                o.item.Normal(name.DEF_ART))
+}
+
+// ParseTrade handles the TRADE subcommands:
+//
+//  TRADE [WITH] <person>
+//  TRADE OFFER <thing>
+//  TRADE AGREE
+//  TRADE CANCEL
+//  TRADE
+//
+func ParseTrade(subj *PlayerChar, verb string, toks []string, text string) {
+  if len(toks) == 0 {
+    if subj.trade == nil {
+      subj.QWrite("You are not trading with anyone.")
+    } else {
+      subj.QWrite("%s", subj.trade.describe(subj))
+    }
+    return
+  }
+  
+  switch toks[0] {
+  case "offer":
+    if subj.trade == nil {
+      subj.QWrite("You are not trading with anyone.")
+      return
+    }
+    if len(toks) < 2 {
+      subj.QWrite("Offer what?")
+      return
+    }
+    t := subj.FindLikePut(toks[1:])
+    if t == nil {
+      subj.notFound("You cannot see any %q here.", strings.Join(toks[1:], " "))
+      return
+    }
+    subj.tradeOffer(t)
+  case "agree", "accept":
+    if subj.trade == nil {
+      subj.QWrite("You are not trading with anyone.")
+      return
+    }
+    subj.tradeAgree(text)
+  case "cancel", "decline":
+    if subj.trade == nil {
+      subj.QWrite("You are not trading with anyone.")
+      return
+    }
+    subj.tradeCancel()
+  default:
+    if toks[0] == "with" {
+      toks = toks[1:]
+    }
+    if len(toks) < 1 {
+      subj.QWrite("Trade with whom?")
+      return
+    }
+    t := subj.FindLikeSay(toks)
+    if t == nil {
+      subj.notFound("You cannot see any %q here.", strings.Join(toks, " "))
+      return
+    }
+    tgt, is_pc := t.(*PlayerChar)
+    if !is_pc || (tgt == subj) {
+      subj.QWrite("You can only trade with other people.")
+      return
+    }
+    subj.tradePropose(tgt)
+  }
+}
+
+// tradePropose() asks tgt to trade, or opens the trade window if tgt has
+// already asked.
+//
+func (pp *PlayerChar) tradePropose(tgt *PlayerChar) {
+  if pp.trade != nil {
+    pp.QWrite("You are already trading with someone. (TRADE CANCEL first.)")
+    return
+  }
+  if tgt.trade != nil {
+    pp.QWrite("%s is busy trading with someone else.", util.Cap(tgt.Normal(0)))
+    return
+  }
+  
+  if p := pp.proposal; (p != nil) && (p.from == tgt) {
+    pp.proposal = nil
+    tr := &trade{ who: [2]*PlayerChar{ tgt, pp }, }
+    pp.trade, tgt.trade = tr, tr
+    pp.QWrite("You begin trading with %s. (TRADE OFFER something.)", tgt.Normal(0))
+    tgt.QWrite("%s begins trading with you. (TRADE OFFER something.)",
+               util.Cap(pp.Normal(0)))
+    return
+  }
+  
+  if tgt.proposal != nil {
+    pp.QWrite("%s is already considering a trade.", util.Cap(tgt.Normal(0)))
+    return
+  }
+  p := &offer{ from: pp, to: tgt, }
+  tgt.proposal = p
+  pp.QWrite("You ask %s to trade with you.", tgt.Normal(0))
+  tgt.QWrite("%s would like to trade with you. (TRADE WITH %s to agree.)",
+             util.Cap(pp.Normal(0)), strings.ToUpper(pp.Short(name.NO_ART)))
+  
+  act.Add(OfferTimeout, func() error {
+    if tgt.proposal == p {
+      tgt.proposal = nil
+      pp.QWrite("%s has not agreed to trade with you.", util.Cap(tgt.Normal(0)))
+    }
+    return nil
+  })
+}
+
+func (pp *PlayerChar) tradeOffer(t thing.Thing) {
+  tr := pp.trade
+  me := tr.side(pp)
+  other := tr.who[1-me]
+  
+  if !pp.Body().IsHolding(t) {
+    pp.QWrite("You must be holding %s to offer it.", t.Normal(name.DEF_ART))
+    return
+  }
+  for _, x := range tr.items[me] {
+    if x == t {
+      pp.QWrite("You are already offering %s.", t.Normal(name.DEF_ART))
+      return
+    }
+  }
+  
+  tr.items[me] = append(tr.items[me], t)
+  tr.withdrawAgreement()
+  pp.QWrite("You offer %s in trade.", t.Normal(0))
+  other.QWrite("%s offers %s in trade.", util.Cap(pp.Normal(0)), t.Normal(0))
+}
+
+func (pp *PlayerChar) tradeCancel() {
+  tr := pp.trade
+  other := tr.who[1-tr.side(pp)]
+  tr.close()
+  pp.QWrite("You stop trading with %s.", other.Normal(0))
+  other.QWrite("%s stops trading with you.", util.Cap(pp.Normal(0)))
+}
+
+func (pp *PlayerChar) tradeAgree(text string) {
+  tr := pp.trade
+  me := tr.side(pp)
+  other := tr.who[1-me]
+  
+  tr.agreed[me] = true
+  if !tr.agreed[1-me] {
+    pp.QWrite("You agree to the trade.")
+    other.QWrite("%s agrees to the trade. (TRADE AGREE to complete it.)",
+                 util.Cap(pp.Normal(0)))
+    return
+  }
+  
+  if err_msg := tr.check(text); err_msg != "" {
+    tr.withdrawAgreement()
+    for _, p := range tr.who {
+      p.QWrite("The trade cannot be completed: %s", err_msg)
+    }
+    return
+  }
+  
+  // Everything is in order; now everything changes hands at once.
+  for n, p := range tr.who {
+    for _, t := range tr.items[n] {
+      p.Release(t)
+    }
+  }
+  for n, p := range tr.who {
+    for _, t := range tr.items[1-n] {
+      p.receive(t)
+    }
+  }
+  tr.close()
+  
+  m := msg.New("txt", "%s and %s complete a trade.", util.Cap(tr.who[0].Normal(0)),
+               tr.who[1].Normal(0))
+  for _, p := range tr.who {
+    m.Add(p, "txt", "The trade is complete.")
+  }
+  pp.where.Place.(*room.Room).Deliver(m)
+}
+
+// check() verifies the trade can be completed, returning a description of
+// the problem if it can't, or the empty string if it can. Each item's
+// "give" and "accept" scripts are checked here.
+//
+func (tr *trade) check(text string) string {
+  a, b := tr.who[0], tr.who[1]
+  if (PlayerChars[a.ref] != a) || (PlayerChars[b.ref] != b) ||
+     (a.where.Place != b.where.Place) {
+    return "you are no longer together."
+  }
+  if (len(tr.items[0]) == 0) && (len(tr.items[1]) == 0) {
+    return "neither of you has offered anything."
+  }
+  
+  for n, p := range tr.who {
+    for _, t := range tr.items[n] {
+      if !p.Body().IsHolding(t) {
+        return p.Normal(0) + " is no longer holding " +
+               t.Normal(name.DEF_ART) + "."
+      }
+    }
+    if p.freeHands() + len(tr.items[n]) < len(tr.items[1-n]) {
+      return p.Normal(0) + " doesn't have enough free hands."
+    }
+    if !p.canSwap(tr.items[n], tr.items[1-n]) {
+      return p.Normal(0) + " can't carry that much more."
+    }
+  }
+  
+  for n, p := range tr.who {
+    other := tr.who[1-n]
+    for _, t := range tr.items[n] {
+      if !scripts.Check(p, t, other, "give", "to", text) ||
+         !scripts.Check(other, t, p, "accept", "from", text) {
+        return t.Normal(name.DEF_ART) + " cannot change hands."
+      }
+    }
+  }
+  return ""
+}
+
+// dropDealings() withdraws any offers or trades involving the PlayerChar;
+// it's called when the PlayerChar logs out.
+//
+func (pp *PlayerChar) dropDealings() {
+  pp.offer, pp.proposal = nil, nil
+  for _, p := range PlayerChars {
+    if (p.offer != nil) && (p.offer.from == pp) {
+      p.offer = nil
+      p.QWrite("%s withdraws %s offer.", util.Cap(pp.Normal(0)), pp.PossPronoun())
+    }
+    if (p.proposal != nil) && (p.proposal.from == pp) {
+      p.proposal = nil
+    }
+  }
+  if tr := pp.trade; tr != nil {
+    other := tr.who[1-tr.side(pp)]
+    tr.close()
+    other.QWrite("%s stops trading with you.", util.Cap(pp.Normal(0)))
+  }
+}
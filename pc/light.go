@@ -0,0 +1,135 @@
+// light.go
+//
+// dta5 PlayerChar light and darkness
+//
+// updated 2026-10-19
+//
+// A dark room.Room (see (room.Room).IsDark()) is lit if anything in it is
+// giving off light (see thing.Luminous): something lying in it, on (or in,
+// if it's open) something lying in it, or carried by someone in it. In an
+// unlit room, a PlayerChar can't see the room or anything in it (except
+// what they're carrying), and the comings and goings of others are only
+// "someone".
+//
+// LIGHT and EXTINGUISH light and put out thing.Lightables.
+//
+package pc
+
+import(
+        "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/thing"; "dta5/util";
+)
+
+// lightIn() returns true if anything in tl (or on, or in if it's open,
+// anything in tl, or carried by a PlayerChar in tl) is giving off light.
+//
+func lightIn(tl *thing.ThingList) bool {
+  if tl == nil {
+    return false
+  }
+  for _, t := range tl.Things {
+    if l, ok := t.(thing.Luminous); ok && l.IsLit() {
+      return true
+    }
+    switch x := t.(type) {
+    case *PlayerChar:
+      if lightIn(x.Inventory) {
+        return true
+      }
+    case thing.Container:
+      if lightIn(x.Side(thing.ON)) {
+        return true
+      }
+      if x.IsOpen() && lightIn(x.Side(thing.IN)) {
+        return true
+      }
+    }
+  }
+  return false
+}
+
+// isDark() returns whether r is dark with nothing to light it.
+//
+func isDark(r *room.Room) bool {
+  return r.IsDark() && !lightIn(r.Contents) && !lightIn(r.Scenery)
+}
+
+// inDark() returns whether the PlayerChar is somewhere too dark to see.
+//
+func (pp *PlayerChar) inDark() bool {
+  if r, ok := pp.where.Place.(*room.Room); ok {
+    return isDark(r)
+  }
+  return false
+}
+
+// nameIn() returns how the PlayerChar appears to others in r: as "someone"
+// if r is dark (and the PlayerChar isn't carrying a light), or by name.
+//
+func (pp *PlayerChar) nameIn(r *room.Room) string {
+  if isDark(r) && !lightIn(pp.Inventory) {
+    return "someone"
+  }
+  return pp.Normal(0)
+}
+
+// type DoFunc func(*PlayerChar,
+//                  string,           verb
+//                  thing.Thing,      direct object
+//                  string,           preposition
+//                  thing.Thing,      indirect object
+//                  string)           complete command text
+
+func DoLight(pp *PlayerChar, verb string, dobj thing.Thing,
+             prep string, iobj thing.Thing, text string) {
+
+  if dobj == nil {
+    pp.QWrite("Light what?")
+    return
+  }
+  l, ok := dobj.(thing.Lightable)
+  if !ok {
+    pp.QWrite("You cannot light %s.", dobj.Normal(name.DEF_ART))
+    return
+  }
+  if l.IsLit() {
+    pp.QWrite("%s is already lit.", util.Cap(dobj.Normal(name.DEF_ART)))
+    return
+  }
+
+  loc := pp.where.Place.(*room.Room)
+  was_dark := isDark(loc)
+  if !l.SetLit(true) {
+    pp.QWrite("%s has burned out.", util.Cap(dobj.Normal(name.DEF_ART)))
+    return
+  }
+
+  m := msg.New("txt", "%s lights %s.", util.Cap(pp.Normal(0)), dobj.Normal(0))
+  m.Add(pp, "txt", "You light %s.", dobj.Normal(0))
+  loc.Deliver(m)
+  if was_dark {
+    DoLook(pp, "look", nil, "", nil, "")
+  }
+}
+
+func DoExtinguish(pp *PlayerChar, verb string, dobj thing.Thing,
+                  prep string, iobj thing.Thing, text string) {
+
+  if dobj == nil {
+    pp.QWrite("Extinguish what?")
+    return
+  }
+  l, ok := dobj.(thing.Lightable)
+  if !ok || !l.IsLit() {
+    pp.QWrite("%s isn't lit.", util.Cap(dobj.Normal(name.DEF_ART)))
+    return
+  }
+
+  loc := pp.where.Place.(*room.Room)
+  m := msg.New("txt", "%s extinguishes %s.", util.Cap(pp.Normal(0)), dobj.Normal(0))
+  m.Add(pp, "txt", "You extinguish %s.", dobj.Normal(0))
+  loc.Deliver(m)
+  l.SetLit(false)
+  if isDark(loc) {
+    pp.QWrite("It is now too dark to see.")
+  }
+}
@@ -1,16 +1,24 @@
 // lock.go
 //
-// dta5 PlayerChar locking and unlocking things.
+// dta5 PlayerChar locking, unlocking, and picking things.
 //
-// updated 2017-08-18
+// updated 2026-10-19
+//
+// All three verbs work on thing.Lockables with a thing.Lock. A key may be
+// named (UNLOCK CHEST WITH BRASS KEY), or be on a key-ring that's named;
+// if no key is named, the PlayerChar tries whatever is in hand.
 //
 package pc
 
-import(
-        "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/scripts";
+import( "dta5/door"; "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/stats";
         "dta5/thing"; "dta5/util";
 )
 
+// The skill (see dta5/stats) checked (against the lock's Difficulty) and
+// trained when picking locks.
+//
+const LockpickSkill = "lockpicking"
+
 // type DoFunc func(*PlayerChar,
 //                  string,           verb
 //                  thing.Thing,      direct object
@@ -18,142 +26,159 @@ import(
 //                  thing.Thing,      indirect object
 //                  string)           complete command text
 
-func DoLock(pp *PlayerChar, verb string,
-            dobj thing.Thing, prep string, iobj thing.Thing,
-            text string) {
-  
+// lockOf() returns dobj's thing.Lock, complaining and returning nil if it
+// doesn't have one (or there is no dobj).
+//
+func (pp *PlayerChar) lockOf(verb string, dobj thing.Thing) *thing.Lock {
   if dobj == nil {
-    if iobj == nil {
-      pp.QWrite("%s what?", util.Cap(verb))
-    } else if prep == "with" {
-      if pp.InHand(iobj) {
-        pp.QWrite("What did you want to %s with %s?", verb, iobj.Normal(name.DEF_ART))
-      } else {
-        pp.QWrite("You are not holding %s.", iobj.Normal(name.DEF_ART))
-      }
-    } else {
-      pp.QWrite("You can't %s something \"%s\" %s.", verb, prep, iobj.Normal(0))
+    pp.QWrite("%s what?", util.Cap(verb))
+    return nil
+  }
+  if lockr, ok := dobj.(thing.Lockable); ok {
+    if l := lockr.Lock(); l != nil {
+      return l
     }
-  } else {
-    if iobj == nil {
-      pp.QWrite("With what did you want to %s %s?", verb, dobj.Normal(name.DEF_ART))
-    } else if prep == "with" {
-      if pp.InHand(iobj) {
-        pp.QWrite("You cannot %s %s with your %s.", verb,
-                  dobj.Normal(name.DEF_ART), iobj.Normal(name.NO_ART))
-      } else {
-        pp.QWrite("You are not holding %s.", iobj.Normal(name.DEF_ART))
-      }
-    } else {
-      pp.QWrite("How can you %s %s \"%s\" %s?", verb,
-                dobj.Normal(0), prep, iobj.Normal(0))
+  }
+  pp.QWrite("%s doesn't have a lock.", util.Cap(dobj.Normal(name.DEF_ART)))
+  return nil
+}
+
+// findKey() returns a key that fits l: either in (or on) iobj if it's
+// given, or in (or on) whatever is in the PlayerChar's hands. It complains
+// and returns nil if there isn't one.
+//
+func (pp *PlayerChar) findKey(l *thing.Lock, dobj, iobj thing.Thing) thing.Thing {
+  if iobj != nil {
+    if !pp.InHand(iobj) {
+      pp.QWrite("You are not holding %s.", iobj.Normal(name.DEF_ART))
+      return nil
+    }
+    if k := l.KeyFor(iobj); k != nil {
+      return k
+    }
+    pp.QWrite("%s doesn't fit %s.", util.Cap(iobj.Normal(name.DEF_ART)),
+              dobj.Normal(name.DEF_ART))
+    return nil
+  }
+  
+  for _, t := range pp.held() {
+    if k := l.KeyFor(t); k != nil {
+      return k
     }
   }
+  pp.QWrite("You aren't holding a key that fits %s.", dobj.Normal(name.DEF_ART))
+  return nil
 }
 
-func LockedScript(obj, subj, dobj, iobj thing.Thing,
-                  verb, prep, text string) bool {
-  if obj != dobj { return true }
+// clickOtherSide() lets the occupants of the room on the other side of a
+// door know something happened to its lock.
+//
+func clickOtherSide(dobj thing.Thing) {
+  if dwy, ok := dobj.(*door.Doorway); ok {
+    od := dwy.Other()
+    m := msg.New("txt", "You hear a click from %s.", od.Normal(name.DEF_ART))
+    od.Loc().Place.(msg.Messageable).Deliver(m)
+  }
+}
+
+func DoLock(pp *PlayerChar, verb string,
+            dobj thing.Thing, prep string, iobj thing.Thing,
+            text string) {
   
-  if dat := dobj.Data("locked_script_unlocked"); dat != nil {
-    if t_dat, ok := dat.(bool); ok {
-      if t_dat == true {
-        return true
-      }
-    } else {
-      scripts.Log("LockedScript(%q, %q, ... %q): obj.Data() is of wrong type (%T)",
-                  obj.Ref(), verb, text, dat)
+  if (iobj != nil) && (prep != "with") {
+    pp.QWrite("You can't %s something \"%s\" %s.", verb, prep, iobj.Normal(0))
+    return
+  }
+  l := pp.lockOf(verb, dobj)
+  if l == nil {
+    return
+  }
+  
+  if verb == "unlock" {
+    if !l.Locked {
+      pp.QWrite("%s is already unlocked.", util.Cap(dobj.Normal(name.DEF_ART)))
+      return
     }
   } else {
-    scripts.Log("LockedScript(%q, %q, ... %q): obj.Data() is nil",
-                obj.Ref(), verb, text)
+    if l.Locked {
+      pp.QWrite("%s is already locked.", util.Cap(dobj.Normal(name.DEF_ART)))
+      return
+    }
+    if o, ok := dobj.(thing.Openable); ok && o.IsOpen() {
+      pp.QWrite("%s is currently open.", util.Cap(dobj.Normal(name.DEF_ART)))
+      return
+    }
   }
   
-  m := msg.New("txt", "%s appears to be locked.", util.Cap(dobj.Normal(name.DEF_ART)))
-  subj.Deliver(m)
-  return false
+  key := pp.findKey(l, dobj, iobj)
+  if key == nil {
+    return
+  }
+  
+  l.Locked = (verb == "lock")
+  m := msg.New("txt", "%s %ss %s with %s.", util.Cap(pp.Normal(0)), verb,
+               dobj.Normal(0), key.Normal(0))
+  m.Add(pp, "txt", "You %s %s with %s.", verb, dobj.Normal(0), key.Normal(0))
+  pp.where.Place.(*room.Room).Deliver(m)
+  clickOtherSide(dobj)
 }
 
-func LockUnlockScript(obj, subj, dobj, iobj thing.Thing,
-                      verb, prep, text string) bool {
-                        
-  if obj != dobj { return true }
-  if iobj == nil { return true }
-  
-  var pp *PlayerChar
-  var ok bool
-  
-  if pp, ok = subj.(*PlayerChar); !ok { return true }
+func DoPick(pp *PlayerChar, verb string,
+            dobj thing.Thing, prep string, iobj thing.Thing,
+            text string) {
   
-  if !pp.InHand(iobj) { return true }
+  if (iobj != nil) && (prep != "with") {
+    pp.QWrite("You can't pick something \"%s\" %s.", prep, iobj.Normal(0))
+    return
+  }
+  l := pp.lockOf(verb, dobj)
+  if l == nil {
+    return
+  }
+  if !l.Locked {
+    pp.QWrite("%s isn't locked.", util.Cap(dobj.Normal(name.DEF_ART)))
+    return
+  }
   
-  if dat := dobj.Data("lock_unlock_script_key"); dat != nil {
-    switch t_dat := dat.(type) {
-    case string:
-      if t_dat == iobj.Ref() {
-        if dat := dobj.Data("locked_script_unlocked"); dat != nil {
-          switch unlkd := dat.(type) {
-          case bool:
-            if unlkd {
-              if verb == "unlock" {
-                m := msg.New("%s is already unlocked.",
-                              util.Cap(dobj.Normal(name.DEF_ART)))
-                subj.Deliver(m)
-              } else if verb == "lock" {
-                if t_dobj, is_openable := dobj.(thing.Openable); is_openable {
-                  if t_dobj.IsOpen() {
-                    m := msg.New("txt,", "%s is currently open.",
-                                  util.Cap(dobj.Normal(name.DEF_ART)))
-                    subj.Deliver(m)
-                    return false
-                  }
-                }
-                dobj.SetData("locked_script_unlocked", false)
-                m := msg.New("txt", "%s locks %s with %s %s.", util.Cap(subj.Normal(0)),
-                              dobj.Normal(0), subj.PossPronoun(),
-                              iobj.Normal(name.NO_ART))
-                m.Add(pp, "txt", "You lock %s with your %s.", dobj.Normal(0),
-                            iobj.Normal(name.NO_ART))
-                pp.where.Place.(*room.Room).Deliver(m)
-              }
-            } else {
-              if verb == "unlock" {
-                dobj.SetData("locked_script_unlocked", true)
-                m := msg.New("txt", "%s unlocks %s with %s %s.", util.Cap(subj.Normal(0)),
-                            dobj.Normal(0), subj.PossPronoun(),
-                            iobj.Normal(name.NO_ART))
-                m.Add(pp, "txt", "You unlock %s with your %s.", dobj.Normal(0),
-                            iobj.Normal(name.NO_ART))
-                pp.where.Place.(*room.Room).Deliver(m)
-              } else {
-                m := msg.New("txt", "%s is already locked.",
-                              util.Cap(dobj.Normal(name.DEF_ART)))
-                subj.Deliver(m)
-              }
-            }
-          default:
-            scripts.Log("LockUnlockScript(%q): obj.Data(\"locked_script_unlocked\") is wrong type (%T)", obj.Ref(), dat)
-            return true
-          }
-        } else {
-          scripts.Log("LockUnlockScript(%q): obj.Data(\"locked_script_unlocked\") is nil)", obj.Ref(), dat)
-          return true
-        }
-        return false
-      } else {
-        return true
-      }
-    default:
-      scripts.Log("LockUnlockScript(%q): obj.Data(\"lock_unlock_script_key\") is wrong type (%T)", obj.Ref(), dat)
+  var pick thing.Thing = nil
+  if iobj != nil {
+    if !pp.InHand(iobj) {
+      pp.QWrite("You are not holding %s.", iobj.Normal(name.DEF_ART))
+      return
+    }
+    if iobj.Match([]string{ thing.LockpickNoun }) {
+      pick = iobj
     }
   } else {
-    scripts.Log("LockUnlockScript(%q): obj.Data() is nil", obj.Ref())
+    for _, t := range pp.held() {
+      if t.Match([]string{ thing.LockpickNoun }) {
+        pick = t
+        break
+      }
+    }
+  }
+  if pick == nil {
+    pp.QWrite("You need to be holding a %s to do that.", thing.LockpickNoun)
+    return
   }
   
-  return true
-}
-
-func init() {
-  scripts.Scripts["locked_script"] = LockedScript
-  scripts.Scripts["lock_unlock_script"] = LockUnlockScript
+  rm := pp.where.Place.(*room.Room)
+  if !stats.Check(pp, LockpickSkill, l.Difficulty) {
+    stats.Award(pp, LockpickSkill, 1)
+    m := msg.New("txt", "%s fiddles with %s, to no effect.",
+                 util.Cap(pp.Normal(0)), dobj.Normal(0))
+    m.Add(pp, "txt", "You work %s in %s, but fail to pick the lock.",
+          pick.Normal(name.DEF_ART), dobj.Normal(name.DEF_ART))
+    rm.Deliver(m)
+    return
+  }
+  
+  stats.Award(pp, LockpickSkill, 1 + l.Difficulty / 10)
+  l.Locked = false
+  m := msg.New("txt", "%s picks the lock on %s with %s.", util.Cap(pp.Normal(0)),
+               dobj.Normal(0), pick.Normal(0))
+  m.Add(pp, "txt", "You work %s in %s until the lock gives with a click.",
+        pick.Normal(name.DEF_ART), dobj.Normal(name.DEF_ART))
+  rm.Deliver(m)
+  clickOtherSide(dobj)
 }
@@ -2,14 +2,14 @@
 //
 // dta5 PlayerChar look verb
 //
-// updated 2017-08-18
+// updated 2026-10-19
 //
 package pc
 
 import( "fmt"; "strings";
         "github.com/delicb/gstring";
         "dta5/body"; "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/thing";
-        "dta5/util";
+        "dta5/util"; "dta5/vehicle"; "dta5/zone";
 )
 
 // type DoFunc func(*PlayerChar,
@@ -23,6 +23,24 @@ func DoLook(pp *PlayerChar, verb string,
             dobj thing.Thing, prep string, iobj thing.Thing,
             text string) {
   
+  // In the dark, the PlayerChar can still look at (or in) what they're
+  // carrying, but nothing else.
+  if pp.inDark() {
+    if (dobj == nil) && (iobj == nil) {
+      pp.Send(msg.Env{Type: "headline", Text: "Darkness"})
+      pp.QWrite("\n* Darkness *\n\nIt is too dark to see anything here.")
+      return
+    }
+    target := dobj
+    if target == nil {
+      target = iobj
+    }
+    if !pp.carries(target) {
+      pp.QWrite("It is too dark to see.")
+      return
+    }
+  }
+  
   if iobj != nil {
     if dobj != nil {
       pp.QWrite("You look at %s %s %s.", dobj.Full(0), prep, iobj.Normal(0))
@@ -36,8 +54,15 @@ func DoLook(pp *PlayerChar, verb string,
       }
     } else {
       t_iobj := iobj.(thing.Container)
-      stuff := t_iobj.Side(parsePreps[prep]).EnglishList()
-      pp.QWrite("%s %s you see %s.", util.Cap(prep), t_iobj.Normal(name.DEF_ART), stuff)
+      tl := pp.visible(t_iobj.Side(parsePreps[prep]))
+      pp.QWrite("%s %s you see %s.", util.Cap(prep), t_iobj.Normal(name.DEF_ART), tl.EnglishList())
+      inside := make([]string, 0, 0)
+      for _, t := range tl.Things {
+        inside = append(inside, pp.nestedLines(t, 1)...)
+      }
+      if len(inside) > 0 {
+        pp.QWrite("%s", strings.Join(inside, "\n"))
+      }
     }
   } else {
     if dobj != nil {
@@ -83,11 +108,15 @@ func DoLook(pp *PlayerChar, verb string,
           txt := fmt.Sprintf("{sp} is wearing: \n%s", strings.Join(worn_stuff, "\n"))
           pp.QWrite(gstring.Sprintm(txt, fmap))
         }
+        if ps := t_dobj.postureString(); ps != "" {
+          pp.QWrite("%s is %s.", util.Cap(dobj.SubjPronoun()), ps)
+        }
       }
         
       if t_dobj, ok := dobj.(thing.Container); ok {
         s := t_dobj.Side(thing.ON)
         if s != nil {
+          s = pp.visible(s)
           if len(s.Things) > 0 {
             pp.QWrite("On %s you see %s.", dobj.Short(name.DEF_ART), s.EnglishList())
           }
@@ -109,6 +138,9 @@ func DoLook(pp *PlayerChar, verb string,
       loc := pp.where.Place.(*room.Room)
       
       rm_name := loc.Title
+      if z := zone.Of(loc); z != nil {
+        rm_name = fmt.Sprintf("%s (%s)", rm_name, z.Name)
+      }
       rm_text := loc.Desc()
       
       t_tl := pp.AllButMe()
@@ -119,6 +151,21 @@ func DoLook(pp *PlayerChar, verb string,
       } else {
         also = ""
       }
+      for _, t := range t_tl.Things {
+        if tp, ok := t.(*PlayerChar); ok {
+          if ps := tp.postureString(); ps != "" {
+            also = fmt.Sprintf("%s\n%s is %s.", also, util.Cap(tp.Normal(0)), ps)
+          }
+        }
+      }
+      if ps := pp.postureString(); ps != "" {
+        also = fmt.Sprintf("%s\n\nYou are %s.", also, ps)
+      }
+      if v := vehicle.Of(loc); v != nil {
+        if t, at := v.Thing(), v.At(); (t != nil) && (at != nil) {
+          also = fmt.Sprintf("%s\n\n%s is at %s.", also, util.Cap(t.Normal(name.DEF_ART)), at.Title)
+        }
+      }
       
       pp.Send(msg.Env{Type: "headline", Text: rm_name})
       pp.QWrite("\n* %s *\n\n%s%s", rm_name, rm_text, also)
@@ -7,7 +7,7 @@
 // inventory
 // swap
 //
-// updated 2017-08-10
+// updated 2026-10-19
 //
 package pc
 
@@ -29,6 +29,9 @@ func DoExits(pp *PlayerChar, verb string, dobj thing.Thing,
   loc := pp.where.Place.(*room.Room)
   exit_dirs := make([]string, 0, 0)
   for n, name := range room.NavDirNames {
+    if !pp.seesExit(loc, n) {
+      continue
+    }
     switch e := loc.Nav(n).(type) {
     case *room.Room:
       exit_dirs = append(exit_dirs, name)
@@ -36,6 +39,11 @@ func DoExits(pp *PlayerChar, verb string, dobj thing.Thing,
       exit_dirs = append(exit_dirs, fmt.Sprintf("%s (%s)", name, e.Normal(0)))
     }
   }
+  for _, ex := range loc.Exits() {
+    if pp.seesNamedExit(ex) {
+      exit_dirs = append(exit_dirs, ex.Verb + " " + ex.Name)
+    }
+  }
   
   switch len(exit_dirs) {
   case 0:
@@ -80,11 +88,31 @@ func DoInventory(pp *PlayerChar, verb string, dobj thing.Thing,
   if len(worn_stuff) > 0 {
     f1p := map[string]interface{} { "pp": "your" }
     txt := fmt.Sprintf("You are wearing:\n%s", strings.Join(worn_stuff, "\n"))
-    pp.QWrite(gstring.Sprintm(txt, f1p))
+    pp.QWrite("%s", gstring.Sprintm(txt, f1p))
   } else {
     pp.QWrite("You aren't wearing anything worth mentioning.")
   }
   
+  if pp.coins > 0 {
+    pp.QWrite("You have %s in your purse.", thing.CoinStr(pp.coins))
+  } else {
+    pp.QWrite("Your purse is empty.")
+  }
+  
+  // INVENTORY FULL also lists what's in (and in what's in) all that.
+  if toks := strings.Fields(strings.ToLower(text)); (len(toks) > 1) && thisStartsThat(toks[1], "full") {
+    inside := make([]string, 0, 0)
+    for _, t := range pp.Inventory.Things {
+      inside = append(inside, pp.nestedLines(t, 1)...)
+    }
+    if len(inside) > 0 {
+      pp.QWrite("Among your things:\n%s", strings.Join(inside, "\n"))
+    }
+    pp.QWrite("All told, you are carrying %.1f, of the %.1f you can, with a bulk of %.1f, of the %.1f you can.",
+              pp.Carried(), pp.CarryLimit(),
+              pp.Inventory.TotalBulk().Value, pp.bod.CarryBulk())
+  }
+  pp.QWrite("%s", encumbranceStrs[pp.Encumbrance()])
 }
 
 func DoSwap(pp *PlayerChar, verb string, dobj thing.Thing, prep string,
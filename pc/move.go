@@ -2,71 +2,120 @@
 //
 // dta5 PlayerChar movement actions
 //
-// updated 2017-08-18
+// updated 2026-10-19
 //
 package pc
 
 import( "strings";
         "dta5/door"; "dta5/log"; "dta5/msg"; "dta5/name"; "dta5/room"
-        "dta5/thing"; "dta5/util";
+        "dta5/scripts"; "dta5/thing"; "dta5/util";
 )
 
+// travel() moves pp from one room to another, delivering the given leaving
+// and arriving messages. The rooms' "leave" and "enter" triggers (see
+// dta5/scripts) are fired before and after, respectively; if pp isn't
+// standing, is too encumbered to move (see pc/carry.go), or the "leave"
+// trigger says no, pp stays put and travel() returns false. If pp leads a
+// group (see pc/follow.go), way is how the rest of the group follows: it
+// makes a follower go the same way.
+//
+func (pp *PlayerChar) travel(from, to *room.Room, lv_m, ar_m *msg.Message,
+                             way func(*PlayerChar)) bool {
+  if pp.posture != STANDING {
+    pp.QWrite("You'll have to stand up first.")
+    return false
+  }
+  if !pp.canMove() {
+    return false
+  }
+  if !scripts.Fire(from, scripts.LEAVE, pp, "") {
+    return false
+  }
+  if !pp.tagAlong {
+    pp.wanderOff()
+  }
+  from.Deliver(lv_m)
+  to.Deliver(ar_m)
+  from.Contents.Remove(pp)
+  to.Contents.Add(pp)
+  pp.moved()
+  DoLook(pp, "look", nil, "", nil, "")
+  scripts.Fire(to, scripts.ENTER, pp, "")
+  pp.lead(from, to, way)
+  return true
+}
+
+// throughDoorway() returns the room.Room that going through dwy leads to,
+// and the message for the PlayerChar's arrival there, or nil (and tells
+// the PlayerChar why) if dwy doesn't lead anywhere.
+//
+func (pp *PlayerChar) throughDoorway(dwy *door.Doorway) (*room.Room, *msg.Message) {
+  o_dwy := dwy.Other()
+  oname := o_dwy.Normal(0)
+
+  switch o_cont := o_dwy.Loc().Place.(type) {
+  case *room.Room:
+    return o_cont, msg.New("txt", "%s arrives through %s.", util.Cap(pp.nameIn(o_cont)), oname)
+  case thing.Container:
+    o_cont_t := o_cont.(thing.Thing)
+    if tgt_rm, ok := o_cont_t.Loc().Place.(*room.Room); ok {
+      return tgt_rm, msg.New("txt", "%s arrives through %s %s.", util.Cap(pp.nameIn(tgt_rm)),
+                             oname, o_dwy.Loc().String())
+    }
+    log(dtalog.ERR, "DoMove(): other *door.Doorway (%q) not contained in a container in a Room.", o_dwy.Ref())
+  default:
+    log(dtalog.ERR, "DoMove(): other *door.Doorway (%q) not contained in room.Room or in thing.Container in a room.Room.", o_dwy.Ref())
+  }
+  pp.QWrite("Some unseen force prevents you. (Really, though, this is a game error.)")
+  return nil, nil
+}
+
+// DoMoveDir() moves the PlayerChar in the given direction; relative
+// directions (room.FORWARD, etc.) are taken relative to the direction the
+// PlayerChar last moved.
+//
 func DoMoveDir(pp *PlayerChar, dir room.NavDir) {
   loc := pp.where.Place.(*room.Room)
+  if dir < 0 {
+    abs, ok := room.Relative(pp.facing, dir)
+    if !ok {
+      pp.QWrite("You cannot go %s from here.", cardDirNames[dir])
+      return
+    }
+    dir = abs
+  }
   tgt := loc.Nav(dir)
   
-  if tgt == nil {
+  if (tgt == nil) || !pp.seesExit(loc, dir) {
     pp.QWrite("You cannot go %s from here.", cardDirNames[dir])
     return
   }
   
   switch t_tgt := tgt.(type) {
   case *room.Room:
-    leave_msg := msg.New("txt", "%s goes %s.", pp.Normal(0), cardDirNames[dir])
+    leave_msg := msg.New("txt", "%s goes %s.", util.Cap(pp.nameIn(loc)), cardDirNames[dir])
     leave_msg.Add(pp, "txt", "You head %s.", cardDirNames[dir])
-    loc.Deliver(leave_msg)
-    arrive_msg := msg.New("txt", "%s arrives.", pp.Normal(0))
-    t_tgt.Deliver(arrive_msg)
-    loc.Contents.Remove(pp)
-    t_tgt.Contents.Add(pp)
-    DoLook(pp, "look", nil, "", nil, "")
+    arrive_msg := msg.New("txt", "%s arrives.", util.Cap(pp.nameIn(t_tgt)))
+    way := func(f *PlayerChar) { DoMoveDir(f, dir) }
+    if pp.travel(loc, t_tgt, leave_msg, arrive_msg, way) {
+      pp.facing = dir
+    }
     
   case *door.Doorway:
     if t_tgt.IsOpen() {
-      sname := util.Cap(pp.Normal(0))
-      o_dwy := t_tgt.Other()
-      oname := o_dwy.Normal(0)
-      var tgt_rm *room.Room
-      var ar_m *msg.Message
-      
-      switch o_cont := o_dwy.Loc().Place.(type) {
-      case *room.Room:
-        ar_m = msg.New("txt", "%s arrives through %s.", sname, oname)
-        tgt_rm = o_cont
-      case thing.Container:
-        o_cont_t := o_cont.(thing.Thing)
-        var no_err bool
-        if tgt_rm, no_err = o_cont_t.Loc().Place.(*room.Room); !no_err {
-          log(dtalog.ERR, "DoMove(): other *door.Doorway (%q) not contained in a container in a Room.", o_dwy.Ref())
-          pp.QWrite("Some unseen force prevents you. (Really, though, this is a game error.)")
-          return
-        }
-        ar_m = msg.New("txt", "%s arrives through %s %s.", sname, o_dwy.Normal(0), o_dwy.Loc().String())
-      default:
-        log(dtalog.ERR, "DoMove(): other *door.Doorway (%q) not contained in room.Room or in thing.Container in a room.Room.", o_dwy.Ref())
-        pp.QWrite("Some unseen force prevents you. (Really, though, this is a game error.)")
+      tgt_rm, ar_m := pp.throughDoorway(t_tgt)
+      if tgt_rm == nil {
         return
       }
       
-      lv_m := msg.New("txt", "%s goes %s through %s.", pp.Normal(0),
+      lv_m := msg.New("txt", "%s goes %s through %s.", util.Cap(pp.nameIn(loc)),
                             cardDirNames[dir], t_tgt.Normal(0))
       lv_m.Add(pp, "txt", "You head %s through %s.", cardDirNames[dir], t_tgt.Normal(0))
       
-      loc.Deliver(lv_m)
-      tgt_rm.Deliver(ar_m)
-      loc.Contents.Remove(pp)
-      tgt_rm.Contents.Add(pp)
-      DoLook(pp, "look", nil, "", nil, "")
+      way := func(f *PlayerChar) { DoMoveDir(f, dir) }
+      if pp.travel(loc, tgt_rm, lv_m, ar_m, way) {
+        pp.facing = dir
+      }
     } else {
       pp.QWrite("%s is closed.", util.Cap(t_tgt.Normal(name.DEF_ART)))
     }
@@ -76,6 +125,106 @@ func DoMoveDir(pp *PlayerChar, dir room.NavDir) {
   }
 }
 
+// exitWords() returns the words of s, without articles.
+//
+func exitWords(s string) []string {
+  words := make([]string, 0, 4)
+  for _, w := range strings.Fields(strings.ToLower(s)) {
+    switch w {
+    case "a", "an", "the", "some":
+    default:
+      words = append(words, w)
+    }
+  }
+  return words
+}
+
+func sameWords(a, b []string) bool {
+  if len(a) != len(b) {
+    return false
+  }
+  for n, w := range a {
+    if w != b[n] {
+      return false
+    }
+  }
+  return true
+}
+
+// findExit() returns the named room.Exit from where the PlayerChar is that
+// the command toks takes, or nil.
+//
+func (pp *PlayerChar) findExit(toks []string) *room.Exit {
+  loc, ok := pp.where.Place.(*room.Room)
+  if !ok {
+    return nil
+  }
+  cmd := exitWords(strings.Join(toks, " "))
+  for _, ex := range loc.Exits() {
+    if !pp.seesNamedExit(ex) {
+      continue
+    }
+    cmds := append([]string{ ex.Verb + " " + ex.Name, "go " + ex.Name, }, ex.Aliases...)
+    for _, c := range cmds {
+      if sameWords(cmd, exitWords(c)) {
+        return ex
+      }
+    }
+  }
+  return nil
+}
+
+// thirdPerson() conjugates the (first word of the) verb phrase v for
+// someone else doing it: "climb down" becomes "climbs down".
+//
+func thirdPerson(v string) string {
+  words := strings.SplitN(v, " ", 2)
+  w := words[0]
+  switch {
+  case w == "go", strings.HasSuffix(w, "s"), strings.HasSuffix(w, "sh"),
+       strings.HasSuffix(w, "ch"), strings.HasSuffix(w, "x"):
+    w = w + "es"
+  default:
+    w = w + "s"
+  }
+  words[0] = w
+  return strings.Join(words, " ")
+}
+
+// DoMoveExit() moves the PlayerChar through the named room.Exit ex.
+//
+func DoMoveExit(pp *PlayerChar, ex *room.Exit) {
+  loc := pp.where.Place.(*room.Room)
+  var tgt_rm *room.Room
+  var ar_m *msg.Message
+
+  switch t_tgt := ex.Dest().(type) {
+  case *room.Room:
+    tgt_rm = t_tgt
+    ar_m = msg.New("txt", "%s arrives.", util.Cap(pp.nameIn(tgt_rm)))
+  case *door.Doorway:
+    if !t_tgt.IsOpen() {
+      pp.QWrite("%s is closed.", util.Cap(t_tgt.Normal(name.DEF_ART)))
+      return
+    }
+    if tgt_rm, ar_m = pp.throughDoorway(t_tgt); tgt_rm == nil {
+      return
+    }
+  default:
+    log(dtalog.ERR, "DoMoveExit(): exit %q from %q leads to %q, which is not a room.Room or door.Doorway",
+                    ex.Name, loc.Ref(), ex.Target)
+    pp.QWrite("Some unseen force prevents you. (Really, though, this is a game error.)")
+    return
+  }
+
+  lv_m := msg.New("txt", "%s %s %s.", util.Cap(pp.nameIn(loc)), thirdPerson(ex.Verb), ex.Name)
+  lv_m.Add(pp, "txt", "You %s %s.", ex.Verb, ex.Name)
+  way := func(f *PlayerChar) { DoMoveExit(f, ex) }
+  if pp.travel(loc, tgt_rm, lv_m, ar_m, way) {
+    pp.facing = room.NavDir(-1)
+  }
+}
+
 // type DoFunc func(*PlayerChar,
 //                  string,           verb
 //                  thing.Thing,      direct object
@@ -95,7 +244,7 @@ func DoMove(pp *PlayerChar, verb string, dobj thing.Thing,
     }
     
     if prep == "behind" {
-      sname := util.Cap(pp.Normal(0))
+      sname := util.Cap(pp.nameIn(loc))
       oname := iobj.Normal(0)
       m := msg.New("txt", "%s walks behind %s.", sname, oname)
       m.Add(pp, "txt", "You walk behind %s.", oname)
@@ -108,7 +257,7 @@ func DoMove(pp *PlayerChar, verb string, dobj thing.Thing,
     }
   } else {
     if dwy, ok := dobj.(*door.Doorway); !ok {
-      sname := util.Cap(pp.Normal(0))
+      sname := util.Cap(pp.nameIn(loc))
       oname := dobj.Normal(0)
       if iobj == nil {
         m := msg.New("txt", "%s walks toward %s.", sname, oname)
@@ -135,45 +284,25 @@ func DoMove(pp *PlayerChar, verb string, dobj thing.Thing,
         return
       }
       
-      var lv_m, ar_m *msg.Message
-      var tgt_rm *room.Room
-      var o_dwy = dwy.Other()
-      var sname = util.Cap(pp.Normal(0))
-      var oname = dwy.Normal(0)
-      
-      switch o_cont := o_dwy.Loc().Place.(type) {
-      case *room.Room:
-        tgt_rm = o_cont
-        ar_m = msg.New("txt", "%s arrives through %s.", sname, o_dwy.Normal(0))
-      case thing.Container:
-        o_cont_t := o_cont.(thing.Thing)
-        var no_err bool
-        if tgt_rm, no_err = o_cont_t.Loc().Place.(*room.Room); !no_err {
-          log(dtalog.ERR, "DoMove(): other *door.Doorway (%q) not contained in a container in a Room.", o_dwy.Ref())
-          pp.QWrite("Some unseen force prevents you. (Really, though, this is a game error.)")
-          return
-        }
-        ar_m = msg.New("txt", "%s arrives through %s %s.", sname, o_dwy.Normal(0), o_dwy.Loc().String())
-      default:
-        log(dtalog.ERR, "DoMove(): other *door.Doorway (%q) not contained in room.Room or in thing.Container in a room.Room.", o_dwy.Ref())
-        pp.QWrite("Some unseen force prevents you. (Really, though, this is a game error.)")
+      tgt_rm, ar_m := pp.throughDoorway(dwy)
+      if tgt_rm == nil {
         return
       }
+      var lv_m *msg.Message
+      var sname = util.Cap(pp.nameIn(loc))
+      var oname = dwy.Normal(0)
       
       if iobj == nil {
         lv_m = msg.New("txt", "%s goes through %s.", sname, oname)
-        lv_m.Add(pp, "txt", "You go through %s", oname)
+        lv_m.Add(pp, "txt", "You go through %s.", oname)
       } else {
         prep_loc := dobj.Loc().String()
         lv_m = msg.New("txt", "%s goes through %s %s.", sname, oname, prep_loc)
         lv_m.Add(pp, "txt", "You go through %s %s.", oname, prep_loc)
       }
       
-      loc.Deliver(lv_m)
-      tgt_rm.Deliver(ar_m)
-      loc.Contents.Remove(pp)
-      tgt_rm.Contents.Add(pp)
-      DoLook(pp, "look", nil, "", nil, "")
+      way := func(f *PlayerChar) { DoMove(f, verb, dobj, prep, iobj, text) }
+      pp.travel(loc, tgt_rm, lv_m, ar_m, way)
     }
   }
 }
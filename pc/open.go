@@ -37,6 +37,12 @@ func DoOpen(pp *PlayerChar, verb string,
       pp.QWrite("You cannot open %s.", dobj.Normal(name.DEF_ART))
       return
     }
+    if lockr, ok := dobj.(thing.Lockable); ok {
+      if l := lockr.Lock(); (l != nil) && l.Locked {
+        pp.QWrite("%s appears to be locked.", util.Cap(dobj.Normal(name.DEF_ART)))
+        return
+      }
+    }
     
     loc := pp.Loc().Place.(*room.Room)
     var act_msg *msg.Message
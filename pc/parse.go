@@ -2,7 +2,7 @@
 //
 // the dta5 player character command parser
 //
-// updated 2017-08-25
+// updated 2026-10-19
 //
 package pc
 
@@ -24,11 +24,51 @@ var parseVerbs []string = []string{
   "blink", "chuckle", "gaze", "frown", "glance", "grin", "lean",
   "nod", "raise", "shake", "shrug", "sigh", "snap", "sneer",
   "snicker", "squint", "stare", "wink",
+  
+  // giving and trading (pc/give.go)
+  "give", "show", "accept", "decline", "trade",
+  
+  // shops (pc/shop.go)
+  "buy", "sell", "list", "appraise",
+  
+  // lock picking (pc/lock.go)
+  "pick",
+  
+  // quests (pc/quest.go)
+  "quests", "journal",
+  
+  // stats (pc/stats.go)
+  "score", "stats",
+  
+  // searching (pc/search.go)
+  "search",
+  
+  // light sources (pc/light.go)
+  "light", "extinguish", "douse",
+  
+  // eating, drinking, and using (pc/consume.go)
+  "eat", "drink", "use",
+  
+  // traveling (pc/travel.go)
+  "travel", "retrace",
+  
+  // vehicles (pc/vehicle.go)
+  "board", "enter", "disembark",
+  
+  // postures (pc/posture.go)
+  "sit", "stand", "lie", "kneel",
+  
+  // following and groups (pc/follow.go)
+  "follow", "unfollow", "group", "gsay",
 }
 
 var verbTranslation map[string]string = map[string]string {
   "take": "get",
   "drop": "put",
+  "journal": "quests",
+  "stats": "score",
+  "douse": "extinguish",
+  "enter": "board",
 }
 
 var parsePreps map[string]byte = map[string]byte {
@@ -36,6 +76,7 @@ var parsePreps map[string]byte = map[string]byte {
   "in": thing.IN,
   "on": thing.ON,
   "under": thing.UNDER,
+  "to": 125,
   "with": 127,
   //"at": 126,
 }
@@ -102,6 +143,72 @@ var parseDispatch map[string]ParseFunc = map[string]ParseFunc {
   "squint":     ParseEmote,
   "stare":      ParseEmote,
   "wink":       ParseEmote,
+  
+  // giving and trading
+  
+  "accept":     ParseIntransitive,
+  "decline":    ParseIntransitive,
+  "give":       ParseLikePut,
+  "show":       ParseLikePut,
+  "trade":      ParseTrade,
+  
+  // shops
+  
+  "appraise":   ParseLikePut,
+  "buy":        ParseBuy,
+  "list":       ParseIntransitive,
+  "sell":       ParseLikePut,
+  
+  // lock picking
+  
+  "pick":       ParseLikeLock,
+  
+  // quests
+  
+  "quests":     ParseIntransitive,
+  
+  // stats
+  
+  "score":      ParseIntransitive,
+  
+  // searching
+  
+  "search":     ParseLikeLook,
+  
+  // light sources
+  
+  "light":      ParseLikePut,
+  "extinguish": ParseLikePut,
+  
+  // eating, drinking, and using
+  
+  "eat":        ParseLikePut,
+  "drink":      ParseLikePut,
+  "use":        ParseLikePut,
+  
+  // traveling
+  
+  "travel":     ParseTravel,
+  "retrace":    ParseTravel,
+  
+  // vehicles
+  
+  "board":      ParseLikeLook,
+  "disembark":  ParseIntransitive,
+  
+  // postures
+  
+  "sit":        ParsePosture,
+  "stand":      ParsePosture,
+  "lie":        ParsePosture,
+  "kneel":      ParsePosture,
+  
+  // following and groups
+  
+  "follow":     ParseLikeLook,
+  "unfollow":   ParseIntransitive,
+  "group":      ParseIntransitive,
+  "gsay":       ParseIntransitive,
 }
 
 var doDispatch map[string]DoFunc = map[string]DoFunc {
@@ -147,6 +254,58 @@ var doDispatch map[string]DoFunc = map[string]DoFunc {
   "squint":     DoEmote,
   "stare":      DoEmote,
   "wink":       DoEmote,
+  
+  // giving and trading
+  
+  "accept":     DoAccept,
+  "decline":    DoDecline,
+  "give":       DoGive,
+  "show":       DoShow,
+  
+  // shops
+  
+  "appraise":   DoAppraise,
+  "list":       DoList,
+  "sell":       DoSell,
+  
+  // lock picking
+  
+  "pick":       DoPick,
+  
+  // quests
+  
+  "quests":     DoQuests,
+  
+  // stats
+  
+  "score":      DoScore,
+  
+  // searching
+  
+  "search":     DoSearch,
+  
+  // light sources
+  
+  "light":      DoLight,
+  "extinguish": DoExtinguish,
+  
+  // eating, drinking, and using
+  
+  "eat":        DoConsume,
+  "drink":      DoConsume,
+  "use":        DoConsume,
+  
+  // vehicles
+  
+  "board":      DoBoard,
+  "disembark":  DoDisembark,
+  
+  // following and groups
+  
+  "follow":     DoFollow,
+  "unfollow":   DoUnfollow,
+  "group":      DoGroup,
+  "gsay":       DoGroupSay,
 }
 
 var cardDirs map[string]room.NavDir = map[string]room.NavDir {
@@ -166,13 +325,14 @@ var cardDirNames map[room.NavDir]string = map[room.NavDir]string {
   room.N: "north", room.NE: "northeast", room.E: "east",
   room.SE: "southeast", room.S: "south", room.SW: "southwest",
   room.W: "west", room.NW: "northwest", room.UP: "up", room.DOWN: "down",
-  room.OUT: "out", }
+  room.OUT: "out", room.FORWARD: "forward", room.BACK: "back",
+  room.LEFT: "left", room.RIGHT: "right", }
 
 var emoteDirs map[string]room.NavDir = map[string]room.NavDir {
-  "forward":  room.NavDir(-1),
-  "back":     room.NavDir(-2),
-  "left":     room.NavDir(-3),
-  "right":    room.NavDir(-4),
+  "forward":  room.FORWARD,
+  "back":     room.BACK,
+  "left":     room.LEFT,
+  "right":    room.RIGHT,
 }
 
 func thisStartsThat(this, that string) bool {
@@ -197,9 +357,25 @@ func matchVerb(token string) string {
   return ""
 }
 
+// takeQty() removes a leading quantity (like the "5" in GET 5 COINS) from
+// the direct object tokens, remembering it in the PlayerChar's qty field
+// for the verb to use.
+//
+func (pp *PlayerChar) takeQty(toks []string) []string {
+  if len(toks) < 2 {
+    return toks
+  }
+  if n, ok := name.NumberValue(toks[0]); ok {
+    pp.qty = n
+    return toks[1:]
+  }
+  return toks
+}
+
 func (pp *PlayerChar) Parse(cmd string) error {
   
   pp.Send(msg.Env{Type: "echo", Text: cmd})
+  pp.stopTrip()
   
   toks := strings.Fields(strings.ToLower(cmd))
   if len(toks) == 0 {
@@ -207,17 +383,45 @@ func (pp *PlayerChar) Parse(cmd string) error {
     return nil
   }
   
+  if q := pp.question; q != nil {
+    pp.question = nil
+    if t := q.choose(toks); t != nil {
+      if pp.answers == nil {
+        pp.answers = make(map[string]thing.Thing)
+      }
+      pp.answers[q.phrase] = t
+      err := pp.Parse(q.cmd)
+      // Keep the answers if it's asking about another phrase (see ambig.go).
+      if pp.question == nil {
+        pp.answers = nil
+      }
+      return err
+    }
+    pp.answers = nil
+  }
+  pp.curCmd = cmd
+  pp.qty = 0
+  
   // process shortcuts
   if (cmd[0] == '"') || (cmd[0] == '\'') {
     DoSay(pp, "", nil, "", nil, cmd)
     return nil
   }
   
+  if ex := pp.findExit(toks); ex != nil {
+    DoMoveExit(pp, ex)
+    return nil
+  }
+  
   if len(toks) == 1 {
     if dir, ok := cardDirs[toks[0]]; ok {
       DoMoveDir(pp, dir)
       return nil
     }
+    if dir, ok := emoteDirs[toks[0]]; ok {
+      DoMoveDir(pp, dir)
+      return nil
+    }
     
     if toks[0] == "quit" {
       return pp.Logout("You have quit the game.")
@@ -256,11 +460,11 @@ func FindInInventory(pp *PlayerChar, toks []string, ord int) (thing.Thing, int)
 func FindInSurroundingsFirst(pp *PlayerChar, toks []string, ord int) (thing.Thing, int) {
   loc := pp.where.Place.(*room.Room)
   
-  t, remain := loc.Contents.Find(toks, ord)
+  t, remain := pp.visible(loc.Contents).Find(toks, ord)
   if t != nil {
     return t, 0
   }
-  t, remain = loc.Scenery.Find(toks, remain)
+  t, remain = pp.visible(loc.Scenery).Find(toks, remain)
   if t != nil {
     return t, 0
   }
@@ -274,11 +478,11 @@ func FindInInventoryFirst(pp *PlayerChar, toks []string, ord int) (thing.Thing,
   }
   
   loc := pp.where.Place.(*room.Room)
-  t, remain = loc.Contents.Find(toks, remain)
+  t, remain = pp.visible(loc.Contents).Find(toks, remain)
   if t != nil {
     return t, 0
   }
-  return loc.Scenery.Find(toks, remain)
+  return pp.visible(loc.Scenery).Find(toks, remain)
 }
 
 func FindPlayerChar(pp *PlayerChar, toks []string, ord int) (thing.Thing, int) {
@@ -303,10 +507,17 @@ func FindPlayerChar(pp *PlayerChar, toks []string, ord int) (thing.Thing, int) {
 }
 
 func (pp *PlayerChar) FindLikeLook(toks []string) thing.Thing {
+  if t, is_pn := pp.antecedent(toks); is_pn {
+    return t
+  }
+  
+  phrase := toks
   find_func := FindInSurroundingsFirst
   
-  if toks[0] == "my" {
-    toks = toks[1:]
+  if (toks[0] == "my") || pp.inDark() {
+    if toks[0] == "my" {
+      toks = toks[1:]
+    }
     find_func = FindInInventory
   }
   if len(toks) < 1 {
@@ -324,11 +535,24 @@ func (pp *PlayerChar) FindLikeLook(toks []string) thing.Thing {
     return nil
   }
   
-  t, _ := find_func(pp, toks, ord)
+  var t thing.Thing
+  if has_ord {
+    t, _ = find_func(pp, toks, ord)
+  } else {
+    t = pp.pick(phrase, func(n int) (thing.Thing, int) {
+      return find_func(pp, toks, n)
+    })
+  }
+  pp.remember(t)
   return t
 }
 
 func (pp *PlayerChar) FindLikePut(toks []string) thing.Thing {
+  if t, is_pn := pp.antecedent(toks); is_pn {
+    return t
+  }
+  
+  phrase := toks
   find_func := FindInInventoryFirst
   
   if toks[0] == "my" {
@@ -349,11 +573,23 @@ func (pp *PlayerChar) FindLikePut(toks []string) thing.Thing {
     return nil
   }
   
-  t, _ := find_func(pp, toks, ord)
+  var t thing.Thing
+  if has_ord {
+    t, _ = find_func(pp, toks, ord)
+  } else {
+    t = pp.pick(phrase, func(n int) (thing.Thing, int) {
+      return find_func(pp, toks, n)
+    })
+  }
+  pp.remember(t)
   return t
 }
 
 func (pp *PlayerChar) FindLikeSay(toks []string) thing.Thing {
+  if t, is_pn := pp.antecedent(toks); is_pn {
+    return t
+  }
+  
   var ord int = 0
   var has_ord bool = false
   pc_find_toks := toks
@@ -365,14 +601,20 @@ func (pp *PlayerChar) FindLikeSay(toks []string) thing.Thing {
     return nil
   }
   
-  t, rem := FindPlayerChar(pp, pc_find_toks, ord)
-  if t != nil {
-    return t
-  }
+  var t thing.Thing
+  var rem int
   
   if has_ord {
-    t, rem = FindInSurroundingsFirst(pp, pc_find_toks, rem)
+    t, rem = FindPlayerChar(pp, pc_find_toks, ord)
+    if t == nil {
+      t, rem = FindInSurroundingsFirst(pp, pc_find_toks, rem)
+    }
+  } else if t, _ = FindPlayerChar(pp, toks, 0); t != nil {
+    t = pp.pick(toks, func(n int) (thing.Thing, int) {
+      return FindPlayerChar(pp, toks, n)
+    })
   } else {
+    phrase := toks
     find_func := FindInSurroundingsFirst
     if toks[0] == "my" {
       toks = toks[1:]
@@ -387,27 +629,53 @@ func (pp *PlayerChar) FindLikeSay(toks []string) thing.Thing {
       if len(toks) < 1 {
         return nil
       }
+      t, rem = find_func(pp, toks, ord)
+    } else {
+      t = pp.pick(phrase, func(n int) (thing.Thing, int) {
+        return find_func(pp, toks, n)
+      })
     }
-    t, rem = find_func(pp, toks, ord)
   }
+  pp.remember(t)
   return t
 }
 
-func FindInThingList(tl *thing.ThingList, toks []string) thing.Thing {
+func FindInThingList(pp *PlayerChar, tl *thing.ThingList, toks []string) thing.Thing {
+  tl = pp.visible(tl)
+  if t, is_pn := pp.antecedent(toks); is_pn {
+    if (t != nil) && tl.Contains(t) {
+      return t
+    }
+    return nil
+  }
+  
+  phrase := toks
   var ord int = 0
   var has_ord bool = false
   ord, has_ord = ordinals[toks[0]]
   if has_ord {
     toks = toks[1:]
   }
-  t, _ := tl.Find(toks, ord)
+  var t thing.Thing
+  if has_ord {
+    t, _ = tl.Find(toks, ord)
+  } else {
+    t = pp.pick(phrase, func(n int) (thing.Thing, int) {
+      return tl.Find(toks, n)
+    })
+  }
+  pp.remember(t)
   return t
 }
 
-// ParseIntransitive() ignores everything but the verb.
+// ParseIntransitive() ignores everything but the verb. Only scripts bound
+// to the subject (see dta5/scripts) can apply.
 //
 func ParseIntransitive(subj *PlayerChar, verb string, toks []string, text string) {
-  doDispatch[verb](subj, verb, nil, "", nil, text)
+  if scripts.Check(subj, nil, nil, verb, "", text) {
+    doDispatch[verb](subj, verb, nil, "", nil, text)
+    scripts.After(subj, nil, nil, verb, "", text)
+  }
 }
 
 // ParseLikeLook() checks for the following options
@@ -459,10 +727,12 @@ func ParseLikeLook(subj *PlayerChar, verb string, toks []string, text string) {
     }
   }
   
+  dobj_toks = subj.takeQty(dobj_toks)
+  
   if len(iobj_toks) > 0 {
     iobj = subj.FindLikeLook(iobj_toks)
     if iobj == nil {
-      subj.QWrite("You can not see any %q here.",
+      subj.notFound("You can not see any %q here.",
                   strings.Join(iobj_toks, " "))
       return
     }
@@ -479,10 +749,19 @@ func ParseLikeLook(subj *PlayerChar, verb string, toks []string, text string) {
         return
       }
       
+      if subj.wantsAll(verb, dobj_toks) {
+        stuff := subj.gatherAll(s.Things, dobj_toks)
+        if verb == "get" {
+          stuff = liftable(stuff)
+        }
+        doAll(subj, verb, stuff, prep, iobj, text)
+        return
+      }
+      
       if len(dobj_toks) > 0 {
-        dobj = FindInThingList(s, dobj_toks)
+        dobj = FindInThingList(subj, s, dobj_toks)
         if dobj == nil {
-          subj.QWrite("You cannot find any \"%s\" %s %s.",
+          subj.notFound("You cannot find any \"%s\" %s %s.",
                       strings.Join(dobj_toks, " "), prep,
                       t_iobj.Normal(name.DEF_ART))
           return
@@ -492,10 +771,22 @@ func ParseLikeLook(subj *PlayerChar, verb string, toks []string, text string) {
       subj.QWrite("You cannot see %s %s.", prep, iobj.Normal(0))
       return
     }
+  } else if subj.wantsAll(verb, dobj_toks) {
+    if subj.inDark() {
+      subj.QWrite("It is too dark to see anything.")
+      return
+    }
+    loc := subj.where.Place.(*room.Room)
+    stuff := subj.gatherAll(loc.Contents.Things, dobj_toks)
+    if verb == "get" {
+      stuff = liftable(stuff)
+    }
+    doAll(subj, verb, stuff, prep, iobj, text)
+    return
   } else if len(dobj_toks) > 0 {
     dobj = subj.FindLikeLook(dobj_toks)
     if dobj == nil {
-      subj.QWrite("You can not see any %q here.",
+      subj.notFound("You can not see any %q here.",
                   strings.Join(dobj_toks, " "))
       return
     }
@@ -503,6 +794,7 @@ func ParseLikeLook(subj *PlayerChar, verb string, toks []string, text string) {
   
   if scripts.Check(subj, dobj, iobj, verb, prep, text) {
     doDispatch[verb](subj, verb, dobj, prep, iobj, text)
+    scripts.After(subj, dobj, iobj, verb, prep, text)
   }
   
 }
@@ -552,10 +844,15 @@ func ParseLikePut(subj *PlayerChar, verb string, toks []string, text string) {
     return
   }
   
-  if len(dobj_toks) > 0 {
+  dobj_toks = subj.takeQty(dobj_toks)
+  var all bool = subj.wantsAll(verb, dobj_toks)
+  
+  if all {
+    // the objects get gathered after the indirect object is found
+  } else if len(dobj_toks) > 0 {
     dobj = subj.FindLikePut(dobj_toks)
     if dobj == nil {
-      subj.QWrite("You cannot see any %q here.",
+      subj.notFound("You cannot see any %q here.",
                   strings.Join(dobj_toks, " "))
       return
     }
@@ -564,14 +861,26 @@ func ParseLikePut(subj *PlayerChar, verb string, toks []string, text string) {
   if len(iobj_toks) > 0 {
     iobj = subj.FindLikeLook(iobj_toks)
     if iobj == nil {
-      subj.QWrite("You cannot see any %q here.",
+      subj.notFound("You cannot see any %q here.",
                   strings.Join(iobj_toks, " "))
       return
     }
   }
   
+  if all {
+    stuff := make([]thing.Thing, 0, 2)
+    for _, t := range subj.gatherAll(subj.held(), dobj_toks) {
+      if t != iobj {
+        stuff = append(stuff, t)
+      }
+    }
+    doAll(subj, verb, stuff, prep, iobj, text)
+    return
+  }
+  
   if scripts.Check(subj, dobj, iobj, verb, prep, text) {
     doDispatch[verb](subj, verb, dobj, prep, iobj, text)
+    scripts.After(subj, dobj, iobj, verb, prep, text)
   }
 }
 
@@ -612,7 +921,7 @@ func ParseLikeLock(subj *PlayerChar, verb string, toks []string, text string) {
   if len(dobj_toks) > 0 {
     dobj = subj.FindLikeLook(dobj_toks)
     if dobj == nil {
-      subj.QWrite("You cannot see any %q here.",
+      subj.notFound("You cannot see any %q here.",
                   strings.Join(dobj_toks, " "))
       return
     }
@@ -621,7 +930,7 @@ func ParseLikeLock(subj *PlayerChar, verb string, toks []string, text string) {
   if len(iobj_toks) > 0 {
     iobj = subj.FindLikePut(iobj_toks)
     if iobj == nil {
-      subj.QWrite("You cannot see any %q here.",
+      subj.notFound("You cannot see any %q here.",
                   strings.Join(iobj_toks, " "))
       return
     }
@@ -629,6 +938,7 @@ func ParseLikeLock(subj *PlayerChar, verb string, toks []string, text string) {
   
   if scripts.Check(subj, dobj, iobj, verb, prep, text) {
     doDispatch[verb](subj, verb, dobj, prep, iobj, text)
+    scripts.After(subj, dobj, iobj, verb, prep, text)
   }
 }
 
@@ -655,6 +965,10 @@ func ParseGo(subj *PlayerChar, verb string, toks []string, text string) {
       DoMoveDir(subj, dir_num)
       return
     }
+    if dir_num, ok := emoteDirs[dir]; ok {
+      DoMoveDir(subj, dir_num)
+      return
+    }
   }
   
   ParseLikeLook(subj, verb, toks, text)
@@ -687,7 +1001,7 @@ func ParseLikePoint(subj *PlayerChar, verb string, toks []string, text string) {
   if len(iobj_toks) > 0 {
     iobj = subj.FindLikePut(iobj_toks)
     if iobj == nil {
-      subj.QWrite("You cannot see any %q here.",
+      subj.notFound("You cannot see any %q here.",
                   strings.Join(iobj_toks, " "))
       return
     }
@@ -708,7 +1022,7 @@ func ParseLikePoint(subj *PlayerChar, verb string, toks []string, text string) {
   if len(dobj_toks) > 0 {
     dobj = subj.FindLikeLook(dobj_toks)
     if dobj == nil {
-      subj.QWrite("You cannot see any %q here.",
+      subj.notFound("You cannot see any %q here.",
                   strings.Join(dobj_toks, " "))
       return
     }
@@ -716,5 +1030,6 @@ func ParseLikePoint(subj *PlayerChar, verb string, toks []string, text string) {
 
   if scripts.Check(subj, dobj, iobj, verb, prep, text) {
     doDispatch[verb](subj, verb, dobj, prep, iobj, text)
+    scripts.After(subj, dobj, iobj, verb, prep, text)
   }
 }
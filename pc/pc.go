@@ -2,14 +2,15 @@
 //
 // dta5 Player Character stuff
 //
-// updated 2017-08-18
+// updated 2026-10-19
 //
 package pc
 
 import( "encoding/json"; "fmt"; "net"; "os"; "path/filepath"; "sync"; "time";
         "golang.org/x/crypto/bcrypt";
-        "dta5/act"; "dta5/body"; "dta5/desc";
-        "dta5/name"; "dta5/load"; "dta5/log"; "dta5/msg"; "dta5/ref";
+        "dta5/act"; "dta5/body"; "dta5/desc"; "dta5/effect";
+        "dta5/name"; "dta5/load"; "dta5/log"; "dta5/msg"; "dta5/quest";
+        "dta5/ref"; "dta5/stats";
         "dta5/room"; "dta5/save"; "dta5/thing";
 )
 
@@ -30,8 +31,13 @@ type PlayerState struct {
   RightHand string
   LeftHand  string
   name.Gender
+  Body      string
   Location  string
   Inventory []string
+  Coins     int
+  Quests    *quest.Log
+  Stats     *stats.Sheet
+  Found     []string
 }
 
 const INV byte = 0
@@ -44,6 +50,27 @@ type PlayerChar struct {
   where     thing.LocVec
   Inventory *thing.ThingList
   bod       *body.BasicBody
+  coins     int
+  quests    *quest.Log
+  stats     *stats.Sheet
+  found     map[string]bool // refs of hidden things (and exits) found
+  facing    room.NavDir     // direction last moved (negative if none)
+  trip      *trip           // where they're TRAVELing to, if anywhere
+  tripFrom  string          // ref of the room they last set off from
+  posture   Posture
+  seat      string          // ref of the furniture they're on, if any
+  group     *group          // who they're following (or leading), if anyone
+  tagAlong  bool            // whether they're following their leader right now
+  winded    bool            // whether they need a moment before moving on
+  qty       int
+  antecedents map[string]thing.Thing
+  antGroup    []thing.Thing
+  curCmd    string
+  question  *choice
+  answers   map[string]thing.Thing // answered choices for curCmd, by phrase
+  offer     *offer
+  proposal  *offer
+  trade     *trade
   conn      net.Conn
   rcvr      *json.Decoder
   sndr      *json.Encoder
@@ -55,6 +82,8 @@ func (p PlayerChar) Mass() thing.TVal { return thing.INFTY }
 func (p PlayerChar) Bulk() thing.TVal { return thing.INFTY }
 func (p PlayerChar) Loc() thing.LocVec { return p.where }
 func (p PlayerChar) Body() body.Interface { return p.bod }
+func (p PlayerChar) QuestLog() *quest.Log { return p.quests }
+func (p PlayerChar) StatSheet() *stats.Sheet { return p.stats }
 
 func (pp *PlayerChar) SetLoc(loc thing.LocVec) {
   pp.where = loc
@@ -200,16 +229,28 @@ func Login(newConn net.Conn) error {
                                  Rest: ps.NameRest, Gender: ps.Gender },
     Inventory: thing.NewThingList(thing.VT_UNLTD, thing.VT_UNLTD, nil, 0),
     passHash: ps.PassHash,
-    bod:  body.NewHumaniod(),
+    bod:  body.New(ps.Body),
+    coins: ps.Coins,
+    quests: ps.Quests.Fix(),
+    stats: ps.Stats.Fix(),
+    found: make(map[string]bool),
+    facing: room.NavDir(-1),
+    antecedents: make(map[string]thing.Thing),
     conn: newConn,
     rcvr: new_rcvr,
     sndr: new_sndr,
     sndlockr: new(sync.Mutex),
   }
+  for _, r := range ps.Found {
+    new_pc.found[r] = true
+  }
   log(dtalog.DBG, "Login(): created PlayerChar struct")
   
   ref.Register(&new_pc)
   new_pc.Inventory.LocVec = thing.LocVec{ Place: &new_pc, Side: INV, }
+  // Each hand holds its own Stack; see (*PlayerChar).heldStack().
+  new_pc.Inventory.NoMerge = true
+  new_pc.setLimits()
   for psdcdr.More() {
     var x []interface{}
     err = psdcdr.Decode(&x)
@@ -224,6 +265,10 @@ func Login(newConn net.Conn) error {
   }
   unlimbo_func := func (t thing.Thing) {
     desc.UnLimbo(t)
+    // Lights carried in lit start burning again (see thing/light.go).
+    if lp, ok := t.(*thing.LightSource); ok {
+      lp.Arm()
+    }
   }
   new_pc.Inventory.Walk(unlimbo_func)
   
@@ -263,6 +308,11 @@ func Login(newConn net.Conn) error {
 }
 
 func (pp *PlayerChar) Logout(mesg string) error {
+  pp.trip = nil
+  pp.dropDealings()
+  pp.leaveGroup("leaves the group")
+  effect.Clear(pp)
+  
   state := PlayerState{
     PassHash:  pp.passHash,
     RefToken:  pp.Ref(),
@@ -270,8 +320,13 @@ func (pp *PlayerChar) Logout(mesg string) error {
     NameFirst: pp.ProperName.First,
     NameRest:  pp.ProperName.Rest,
     Gender:    pp.ProperName.Gender,
+    Body:      pp.bod.Type().Tag,
     Location:  pp.where.Place.Ref(),
     Inventory: make([]string, 0, len(pp.Inventory.Things)),
+    Coins:     pp.coins,
+    Quests:    pp.quests,
+    Stats:     pp.stats,
+    Found:     make([]string, 0, len(pp.found)),
   }
   
   bod := pp.Body()
@@ -294,6 +349,9 @@ func (pp *PlayerChar) Logout(mesg string) error {
   for _, t := range pp.Inventory.Things {
     state.Inventory = append(state.Inventory, t.Ref())
   }
+  for r := range pp.found {
+    state.Found = append(state.Found, r)
+  }
   s.Encode(state)
   
   for _, t := range pp.Inventory.Things {
@@ -365,7 +423,7 @@ func (pp *PlayerChar) AllButMe() *thing.ThingList {
   nts := make([]thing.Thing, 0, len(src.Things))
   
   for _, t := range src.Things {
-    if t != pp {
+    if (t != pp) && pp.sees(t) {
       nts = append(nts, t)
     }
   }
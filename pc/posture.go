@@ -0,0 +1,213 @@
+// posture.go
+//
+// dta5 PlayerChar sitting, standing, lying, and kneeling
+//
+// updated 2026-10-19
+//
+// A PlayerChar is always standing, sitting, lying, or kneeling, either on
+// the ground or on a piece of furniture: anything with an ON side (see
+// thing.Container), like a bench or a table. A piece of furniture has room
+// for as many people as its "seats" ref.Data says (see the "seats" function
+// in dta5/load/build), or DefaultSeats if it doesn't say. A PlayerChar has
+// to stand up before going anywhere.
+//
+package pc
+
+import( "fmt"; "strings";
+        "dta5/msg"; "dta5/name"; "dta5/ref"; "dta5/room"; "dta5/scripts";
+        "dta5/thing"; "dta5/util"; "dta5/vehicle";
+)
+
+type Posture byte
+
+const(  STANDING Posture = iota
+        SITTING
+        LYING
+        KNEELING
+)
+
+var postureNames = map[Posture]string {
+  STANDING: "standing", SITTING: "sitting", LYING: "lying", KNEELING: "kneeling",
+}
+
+// The posture each verb puts the PlayerChar in, and how the verb is put in
+// messages.
+//
+var postureVerbs = map[string]Posture {
+  "stand": STANDING, "sit": SITTING, "lie": LYING, "kneel": KNEELING,
+}
+var postureActs = map[Posture]string {
+  SITTING: "sit down", LYING: "lie down", KNEELING: "kneel",
+}
+
+// How many people fit on a piece of furniture without a "seats" ref.Data.
+//
+var DefaultSeats int = 1
+
+func (p PlayerChar) Posture() Posture { return p.posture }
+
+// seatThing() returns the furniture the PlayerChar is on, or nil (and
+// forgets it, if it's not where the PlayerChar is anymore).
+//
+func (pp *PlayerChar) seatThing() thing.Thing {
+  if pp.seat == "" {
+    return nil
+  }
+  t, ok := ref.Deref(pp.seat).(thing.Thing)
+  if !ok || (t.Loc().Place != pp.where.Place) {
+    pp.seat = ""
+    return nil
+  }
+  return t
+}
+
+// postureString() describes how the PlayerChar is ("sitting on the
+// bench", "lying down"), or returns "" if they're just standing around.
+//
+func (pp *PlayerChar) postureString() string {
+  if pp.posture == STANDING {
+    return ""
+  }
+  if t := pp.seatThing(); t != nil {
+    return fmt.Sprintf("%s on %s", postureNames[pp.posture], t.Normal(name.DEF_ART))
+  }
+  if pp.posture == KNEELING {
+    return "kneeling"
+  }
+  return postureNames[pp.posture] + " down"
+}
+
+// isFurniture() returns whether t is something people can sit on. (Not
+// other people, and not vehicles, which people get aboard instead.)
+//
+func isFurniture(t thing.Thing) bool {
+  if _, ok := t.(*PlayerChar); ok || vehicle.Is(t) {
+    return false
+  }
+  c, ok := t.(thing.Container)
+  return ok && (c.Side(thing.ON) != nil)
+}
+
+// seats() returns how many people fit on the furniture t.
+//
+func seats(t thing.Thing) int {
+  if n, ok := ref.GetData(t, "seats").(float64); ok {
+    return int(n)
+  }
+  return DefaultSeats
+}
+
+// occupants() returns how many PlayerChars in loc are on the furniture t.
+//
+func occupants(loc *room.Room, t thing.Thing) int {
+  var n int = 0
+  for _, x := range loc.Contents.Things {
+    if xp, ok := x.(*PlayerChar); ok && (xp.seatThing() == t) {
+      n++
+    }
+  }
+  return n
+}
+
+// ParsePosture handles SIT, STAND, LIE, and KNEEL, with or without
+// "up"/"down" and furniture: SIT, SIT DOWN, SIT ON BENCH, LIE DOWN ON
+// TABLE, STAND UP.
+//
+func ParsePosture(subj *PlayerChar, verb string, toks []string, text string) {
+  if (len(toks) > 0) && ((toks[0] == "up") || (toks[0] == "down")) {
+    toks = toks[1:]
+  }
+  var prep string
+  if (len(toks) > 0) && ((toks[0] == "on") || (toks[0] == "in") || (toks[0] == "at")) {
+    prep = "on"
+    toks = toks[1:]
+  }
+
+  var dobj thing.Thing
+  if len(toks) > 0 {
+    if verb == "stand" {
+      subj.QWrite("Just STAND (or STAND UP) will do.")
+      return
+    }
+    dobj = subj.FindLikeLook(toks)
+    if dobj == nil {
+      subj.notFound("You can not see any %q here.", strings.Join(toks, " "))
+      return
+    }
+  }
+
+  if scripts.Check(subj, dobj, nil, verb, prep, text) {
+    DoPosture(subj, verb, dobj, prep, nil, text)
+    scripts.After(subj, dobj, nil, verb, prep, text)
+  }
+}
+
+// type DoFunc func(*PlayerChar,
+//                  string,           verb
+//                  thing.Thing,      direct object
+//                  string,           preposition
+//                  thing.Thing,      indirect object
+//                  string)           complete command text
+
+func DoPosture(pp *PlayerChar, verb string, dobj thing.Thing,
+               prep string, iobj thing.Thing, text string) {
+  loc := pp.where.Place.(*room.Room)
+  sname := util.Cap(pp.nameIn(loc))
+  want := postureVerbs[verb]
+  seat := pp.seatThing()
+
+  if want == STANDING {
+    if pp.posture == STANDING {
+      pp.QWrite("You are already standing.")
+      return
+    }
+    var m *msg.Message
+    if seat != nil {
+      m = msg.New("txt", "%s gets up from %s.", sname, seat.Normal(name.DEF_ART))
+      m.Add(pp, "txt", "You get up from %s.", seat.Normal(name.DEF_ART))
+    } else {
+      m = msg.New("txt", "%s stands up.", sname)
+      m.Add(pp, "txt", "You stand up.")
+    }
+    pp.posture, pp.seat = STANDING, ""
+    loc.Deliver(m)
+    return
+  }
+
+  if dobj == nil {
+    dobj = seat
+  } else if dobj != seat {
+    dname := dobj.Normal(name.DEF_ART)
+    if !isFurniture(dobj) {
+      pp.QWrite("You can't %s on %s.", verb, dname)
+      return
+    }
+    if dobj.Loc().Place != ref.Interface(loc) {
+      pp.QWrite("You'd have to put %s down first.", dname)
+      return
+    }
+    if occupants(loc, dobj) >= seats(dobj) {
+      pp.QWrite("There's no room for you on %s.", dname)
+      return
+    }
+  }
+  if (want == pp.posture) && (dobj == seat) {
+    pp.QWrite("You are already %s.", pp.postureString())
+    return
+  }
+
+  vp := postureActs[want]
+  var m *msg.Message
+  if dobj != nil {
+    dname := dobj.Normal(name.DEF_ART)
+    m = msg.New("txt", "%s %s on %s.", sname, thirdPerson(vp), dname)
+    m.Add(pp, "txt", "You %s on %s.", vp, dname)
+    pp.seat = dobj.Ref()
+  } else {
+    m = msg.New("txt", "%s %s.", sname, thirdPerson(vp))
+    m.Add(pp, "txt", "You %s.", vp)
+    pp.seat = ""
+  }
+  pp.posture = want
+  loc.Deliver(m)
+}
@@ -0,0 +1,20 @@
+// quest.go
+//
+// dta5 PlayerChar quest journal
+//
+// updated 2026-10-19
+//
+// QUESTS (or JOURNAL) lists the quests (see dta5/quest) the PlayerChar is
+// on, with a description of where they are in each, and the ones they've
+// completed.
+//
+package pc
+
+import( "strings";
+        "dta5/thing";
+)
+
+func DoQuests(pp *PlayerChar, verb string, dobj thing.Thing,
+              prep string, iobj thing.Thing, text string) {
+  pp.QWrite("%s", strings.Join(pp.quests.Journal(), "\n"))
+}
@@ -0,0 +1,224 @@
+// refer.go
+//
+// dta5 PlayerChar pronoun resolution and multiple objects
+//
+// updated 2026-10-19
+//
+// Each PlayerChar remembers the things (and characters) most recently
+// referred to in its commands, so that later commands can refer to them by
+// pronoun:
+//
+//  > get key
+//  > examine it
+//
+// Referents are remembered by the object pronoun of their name.Name ("it",
+// "him", "her", or "them"), so "give it to her" does what one would expect.
+//
+// The verbs in allVerbs (below) can also act on several things at once:
+//
+//  > get all
+//  > get all keys in bin
+//  > drop them
+//
+// A group acted upon this way is remembered as a whole, and "them" refers
+// to it for those verbs.
+//
+package pc
+
+import(
+        "dta5/room"; "dta5/scripts"; "dta5/thing";
+)
+
+var pronounToks = map[string]bool {
+  "it": true, "him": true, "her": true, "them": true,
+}
+
+// allVerbs are the verbs that accept "all", "all <noun>", or "them" (meaning
+// the last group of things acted upon) as a direct object.
+//
+var allVerbs = map[string]bool {
+  "get": true,
+  "put": true,
+}
+
+// remember() sets t as the referent of its object pronoun.
+//
+func (pp *PlayerChar) remember(t thing.Thing) {
+  if (t == nil) || (t == pp) {
+    return
+  }
+  pn := t.ObjPronoun()
+  pp.antecedents[pn] = t
+  if pn == "them" {
+    pp.antGroup = nil
+  }
+}
+
+// rememberGroup() sets the group of things referred to by "them" for the
+// verbs in allVerbs.
+//
+func (pp *PlayerChar) rememberGroup(stuff []thing.Thing) {
+  switch len(stuff) {
+  case 0:
+    return
+  case 1:
+    pp.remember(stuff[0])
+  default:
+    pp.antGroup = stuff
+    delete(pp.antecedents, "them")
+  }
+}
+
+// canReach() returns true if t is somewhere in the PlayerChar's inventory
+// or surroundings (including inside things in either).
+//
+func (pp *PlayerChar) canReach(t thing.Thing) bool {
+  var found bool = false
+  f := func(x thing.Thing) {
+    if x == t {
+      found = true
+    }
+  }
+  loc := pp.where.Place.(*room.Room)
+  pp.Inventory.Walk(f)
+  loc.Contents.Walk(f)
+  loc.Scenery.Walk(f)
+  return found
+}
+
+// antecedent() returns the remembered referent of the pronoun in toks. The
+// returned boolean reports whether toks was a lone pronoun at all; if it
+// was, but its referent can't be found (or is no longer within reach), the
+// returned Thing is nil.
+//
+func (pp *PlayerChar) antecedent(toks []string) (thing.Thing, bool) {
+  if (len(toks) != 1) || !pronounToks[toks[0]] {
+    return nil, false
+  }
+  t := pp.antecedents[toks[0]]
+  if (t == nil) && (toks[0] == "them") && (len(pp.antGroup) > 0) {
+    t = pp.antGroup[0]
+  }
+  if (t == nil) || !pp.canReach(t) {
+    return nil, true
+  }
+  return t, true
+}
+
+// wantsAll() returns true if the direct object tokens toks should be
+// handled by gatherAll() and doAll() for the given verb.
+//
+func (pp *PlayerChar) wantsAll(verb string, toks []string) bool {
+  if !allVerbs[verb] || (len(toks) < 1) {
+    return false
+  }
+  if toks[0] == "all" {
+    return true
+  }
+  return (len(toks) == 1) && (toks[0] == "them") && (len(pp.antGroup) > 1)
+}
+
+// matchLoosely() is like t.Match(toks), but will also try a final token
+// with a plural-looking "s" removed, so "all keys" matches keys.
+//
+func matchLoosely(t thing.Thing, toks []string) bool {
+  if t.Match(toks) {
+    return true
+  }
+  last := toks[len(toks)-1]
+  if (len(last) > 1) && (last[len(last)-1] == 's') {
+    sing := make([]string, len(toks))
+    copy(sing, toks)
+    sing[len(sing)-1] = last[:len(last)-1]
+    return t.Match(sing)
+  }
+  return false
+}
+
+// gatherAll() returns those of the supplied things described by the
+// "all ..." or "them" phrase in toks.
+//
+func (pp *PlayerChar) gatherAll(things []thing.Thing, toks []string) []thing.Thing {
+  stuff := make([]thing.Thing, 0, len(things))
+
+  if toks[0] == "them" {
+    for _, t := range pp.antGroup {
+      for _, x := range things {
+        if x == t {
+          stuff = append(stuff, t)
+          break
+        }
+      }
+    }
+    return stuff
+  }
+
+  noun_toks := toks[1:]
+  for _, t := range things {
+    if (t == pp) || !pp.sees(t) {
+      continue
+    }
+    if (len(noun_toks) == 0) || matchLoosely(t, noun_toks) {
+      stuff = append(stuff, t)
+    }
+  }
+  return stuff
+}
+
+// liftable() filters out the things GET ALL shouldn't even try to pick up.
+//
+func liftable(stuff []thing.Thing) []thing.Thing {
+  lst := make([]thing.Thing, 0, len(stuff))
+  for _, t := range stuff {
+    if _, is_pc := t.(*PlayerChar); is_pc {
+      continue
+    }
+    if (t.Mass().VT == thing.VT_LTD) && (t.Bulk().VT == thing.VT_LTD) {
+      lst = append(lst, t)
+    }
+  }
+  return lst
+}
+
+// held() returns a slice of the things the PlayerChar has in hand.
+//
+func (pp *PlayerChar) held() []thing.Thing {
+  bod := pp.Body()
+  stuff := make([]thing.Thing, 0, 2)
+  for _, slot := range []string{"right_hand", "left_hand"} {
+    if t, _ := bod.HeldIn(slot); t != nil {
+      stuff = append(stuff, t)
+    }
+  }
+  return stuff
+}
+
+func (pp *PlayerChar) handsFull() bool {
+  bod := pp.Body()
+  rh, _ := bod.HeldIn("right_hand")
+  lh, _ := bod.HeldIn("left_hand")
+  return (rh != nil) && (lh != nil)
+}
+
+// doAll() performs the given verb on each of the supplied things in turn,
+// checking scripts for each, and remembers them as a group.
+//
+func doAll(subj *PlayerChar, verb string, stuff []thing.Thing,
+           prep string, iobj thing.Thing, text string) {
+  if len(stuff) == 0 {
+    subj.QWrite("There is nothing like that to %s.", verb)
+    return
+  }
+
+  for _, t := range stuff {
+    if (verb == "get") && subj.handsFull() {
+      subj.QWrite("Your hands are full.")
+      break
+    }
+    if scripts.Check(subj, t, iobj, verb, prep, text) {
+      doDispatch[verb](subj, verb, t, prep, iobj, text)
+      scripts.After(subj, t, iobj, verb, prep, text)
+    }
+  }
+  subj.rememberGroup(stuff)
+}
@@ -2,13 +2,14 @@
 //
 // dta5 PlayerChar speech
 //
-// updated 2017-08-06
+// updated 2026-10-19
 //
 package pc
 
 import( "strings";
         "github.com/delicb/gstring";
-        "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/thing"; "dta5/util";
+        "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/scripts"; "dta5/thing";
+        "dta5/util";
 )
 
 const sayTemplate   = `{subj} {verb}, "{text}{punct}"`
@@ -57,6 +58,9 @@ func DoSay(pp *PlayerChar, verb string, dobj thing.Thing,
   var obj thing.Thing = nil
   if len(tgt_toks) > 0 {
     obj = pp.FindLikeSay(tgt_toks)
+    if (obj == nil) && (pp.question != nil) {
+      return
+    }
   }
   
   if len(toks) < 1 {
@@ -142,5 +146,7 @@ func DoSay(pp *PlayerChar, verb string, dobj thing.Thing,
     m.Add(obj, "speech", gstring.Sprintm(sayToTemplate, f2p))
   }
   
-  pp.where.Place.(*room.Room).Deliver(m)
+  rm := pp.where.Place.(*room.Room)
+  rm.Deliver(m)
+  scripts.Fire(rm, scripts.SAY, pp, string(wordz))
 }
@@ -0,0 +1,218 @@
+// search.go
+//
+// dta5 PlayerChar searching and perception
+//
+// updated 2026-10-19
+//
+// Things can be hidden (see thing.Concealable), as can the exits from a
+// room.Room (see (*room.Room).HideExit()). A PlayerChar can't see, name, or
+// use a hidden thing or exit until they've found it by SEARCHing; what each
+// PlayerChar has found is remembered (and saved with them). A hidden thing
+// stops being hidden once someone picks it up.
+//
+// SEARCH alone searches the room: everything in it and around (behind,
+// under, and on) the things in it, and its exits. SEARCH <thing> (or
+// SEARCH BEHIND <thing>, etc.) searches just that thing. Each hidden thing
+// or exit is found with a skill check (see dta5/stats) against its
+// concealment.
+//
+package pc
+
+import( "strings";
+        "dta5/door"; "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/stats";
+        "dta5/thing"; "dta5/util";
+)
+
+// The skill (see dta5/stats) checked and trained when searching.
+//
+const PerceptionSkill = "perception"
+
+// sees() returns false if t is hidden and the PlayerChar hasn't found it.
+//
+func (pp *PlayerChar) sees(t thing.Thing) bool {
+  if c, ok := t.(thing.Concealable); ok && (c.Concealment() > 0) {
+    return pp.found[t.Ref()]
+  }
+  return true
+}
+
+// seesExit() returns false if the exit from r in direction d is hidden and
+// the PlayerChar hasn't found it (or if it's through a Doorway the
+// PlayerChar can't see).
+//
+func (pp *PlayerChar) seesExit(r *room.Room, d room.NavDir) bool {
+  if (r.ExitConcealment(d) > 0) && !pp.found[r.ExitRef(d)] {
+    return false
+  }
+  if dwy, ok := r.Nav(d).(*door.Doorway); ok {
+    return pp.sees(dwy)
+  }
+  return true
+}
+
+// seesNamedExit() is seesExit() for a named room.Exit.
+//
+func (pp *PlayerChar) seesNamedExit(ex *room.Exit) bool {
+  if dwy, ok := ex.Dest().(*door.Doorway); ok {
+    return pp.sees(dwy)
+  }
+  return true
+}
+
+// visible() returns a thing.ThingList of the things in tl that the
+// PlayerChar can see (or tl itself if that's all of them). The returned
+// ThingList is only for looking things up or listing them; don't Add() to
+// or Remove() from it.
+//
+func (pp *PlayerChar) visible(tl *thing.ThingList) *thing.ThingList {
+  for n, t := range tl.Things {
+    if !pp.sees(t) {
+      nts := make([]thing.Thing, 0, len(tl.Things))
+      nts = append(nts, tl.Things[:n]...)
+      for _, x := range tl.Things[n+1:] {
+        if pp.sees(x) {
+          nts = append(nts, x)
+        }
+      }
+      return &thing.ThingList{ Things: nts, LocVec: tl.LocVec, }
+    }
+  }
+  return tl
+}
+
+// hiddenIn() returns the things in tl the PlayerChar hasn't found yet.
+//
+func (pp *PlayerChar) hiddenIn(tl *thing.ThingList) []thing.Thing {
+  stuff := make([]thing.Thing, 0, 0)
+  if tl == nil {
+    return stuff
+  }
+  for _, t := range tl.Things {
+    if !pp.sees(t) {
+      stuff = append(stuff, t)
+    }
+  }
+  return stuff
+}
+
+// searchable() returns the sides of a container that searching it covers:
+// just the named side if there is one, or all of them (except the inside,
+// if it's closed).
+//
+func searchable(cont thing.Container, prep string) []*thing.ThingList {
+  sides := make([]*thing.ThingList, 0, 4)
+  if prep != "" {
+    if s, ok := parsePreps[prep]; ok && s <= thing.UNDER {
+      if tl := cont.Side(s); tl != nil {
+        sides = append(sides, tl)
+      }
+    }
+    return sides
+  }
+  for _, s := range []byte{ thing.IN, thing.ON, thing.BEHIND, thing.UNDER, } {
+    if s == thing.IN {
+      if o, ok := cont.(thing.Openable); ok && !o.IsOpen() {
+        continue
+      }
+    }
+    if tl := cont.Side(s); tl != nil {
+      sides = append(sides, tl)
+    }
+  }
+  return sides
+}
+
+// type DoFunc func(*PlayerChar,
+//                  string,           verb
+//                  thing.Thing,      direct object
+//                  string,           preposition
+//                  thing.Thing,      indirect object
+//                  string)           complete command text
+
+func DoSearch(pp *PlayerChar, verb string, dobj thing.Thing,
+              prep string, iobj thing.Thing, text string) {
+  
+  if pp.inDark() {
+    pp.QWrite("It is too dark to search for anything.")
+    return
+  }
+  
+  rm := pp.where.Place.(*room.Room)
+  sname := util.Cap(pp.Normal(0))
+  candidates := make([]thing.Thing, 0, 0)
+  exits := make([]room.NavDir, 0, 0)
+  var m *msg.Message
+  
+  tgt := dobj
+  if tgt == nil {
+    tgt = iobj
+  }
+  
+  if tgt == nil {
+    for _, tl := range []*thing.ThingList{ rm.Contents, rm.Scenery, } {
+      candidates = append(candidates, pp.hiddenIn(tl)...)
+      for _, t := range tl.Things {
+        if cont, ok := t.(thing.Container); ok && pp.sees(t) {
+          for _, s := range []byte{ thing.ON, thing.BEHIND, thing.UNDER, } {
+            candidates = append(candidates, pp.hiddenIn(cont.Side(s))...)
+          }
+        }
+      }
+    }
+    for n := range room.NavDirNames {
+      if (rm.Nav(n) != nil) && (rm.ExitConcealment(n) > 0) && !pp.found[rm.ExitRef(n)] {
+        exits = append(exits, n)
+      }
+    }
+    m = msg.New("txt", "%s searches the area.", sname)
+    m.Add(pp, "txt", "You search the area.")
+  } else {
+    cont, ok := tgt.(thing.Container)
+    if !ok {
+      pp.QWrite("There's nowhere on %s for anything to be hidden.", tgt.Normal(name.DEF_ART))
+      return
+    }
+    for _, tl := range searchable(cont, prep) {
+      candidates = append(candidates, pp.hiddenIn(tl)...)
+    }
+    where := tgt.Normal(0)
+    if prep != "" {
+      where = prep + " " + where
+    }
+    m = msg.New("txt", "%s searches %s.", sname, where)
+    m.Add(pp, "txt", "You search %s.", where)
+  }
+  rm.Deliver(m)
+  
+  stats.Award(pp, PerceptionSkill, 1)
+  
+  found := make([]string, 0, len(candidates))
+  for _, t := range candidates {
+    c := t.(thing.Concealable).Concealment()
+    if stats.Check(pp, PerceptionSkill, c) {
+      pp.found[t.Ref()] = true
+      pp.remember(t)
+      found = append(found, t.Normal(0))
+      stats.Award(pp, PerceptionSkill, c / 10)
+    }
+  }
+  found_exits := make([]string, 0, len(exits))
+  for _, d := range exits {
+    c := rm.ExitConcealment(d)
+    if stats.Check(pp, PerceptionSkill, c) {
+      pp.found[rm.ExitRef(d)] = true
+      found_exits = append(found_exits, room.NavDirNames[d])
+      stats.Award(pp, PerceptionSkill, c / 10)
+    }
+  }
+  
+  if len(found) > 0 {
+    pp.QWrite("You find %s!", util.EnglishList(found))
+  }
+  if len(found_exits) > 0 {
+    pp.QWrite("You find a hidden way %s!", strings.Join(found_exits, " and "))
+  }
+  if (len(found) == 0) && (len(found_exits) == 0) {
+    pp.QWrite("You don't find anything.")
+  }
+}
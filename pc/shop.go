@@ -0,0 +1,216 @@
+// shop.go
+//
+// dta5 PlayerChar verbs for dealing with shops
+//
+// updated 2026-10-19
+//
+// LIST, BUY, SELL, and APPRAISE all deal with the first shop.Shop in the
+// PlayerChar's room. Money comes out of (and goes into) the PlayerChar's
+// purse.
+//
+package pc
+
+import( "strconv"; "strings";
+        "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/scripts"; "dta5/shop";
+        "dta5/thing"; "dta5/util";
+)
+
+// shopHere() returns the shop.Shop in the PlayerChar's room, or nil if
+// there isn't one.
+//
+func (pp *PlayerChar) shopHere() *shop.Shop {
+  loc := pp.where.Place.(*room.Room)
+  for _, tl := range []*thing.ThingList{ loc.Scenery, loc.Contents } {
+    for _, t := range tl.Things {
+      if sp, ok := t.(*shop.Shop); ok {
+        return sp
+      }
+    }
+  }
+  return nil
+}
+
+// type DoFunc func(*PlayerChar,
+//                  string,           verb
+//                  thing.Thing,      direct object
+//                  string,           preposition
+//                  thing.Thing,      indirect object
+//                  string)           complete command text
+
+func DoList(pp *PlayerChar, verb string, dobj thing.Thing,
+            prep string, iobj thing.Thing, text string) {
+  
+  sp := pp.shopHere()
+  if sp == nil {
+    pp.QWrite("There is nothing for sale here.")
+    return
+  }
+  if len(sp.Stock) == 0 {
+    pp.QWrite("%s has nothing for sale.", util.Cap(sp.Normal(name.DEF_ART)))
+    return
+  }
+  
+  lines := make([]string, 0, len(sp.Stock) + 1)
+  lines = append(lines, util.Cap(sp.Normal(name.DEF_ART)) + " offers:")
+  for n, st := range sp.Stock {
+    line := "  " + strconv.Itoa(n+1) + ". " + st.Proto.Normal(0) + " for " +
+            thing.CoinStr(st.Price)
+    if st.Count < 1 {
+      line = line + " (sold out)"
+    }
+    lines = append(lines, line)
+  }
+  pp.QWrite("%s", strings.Join(lines, "\n"))
+}
+
+// ParseBuy looks for the thing to buy in the shop's stock list (rather than
+// in the PlayerChar's surroundings), either by name or by its number in
+// the LIST.
+//
+func ParseBuy(subj *PlayerChar, verb string, toks []string, text string) {
+  sp := subj.shopHere()
+  if sp == nil {
+    subj.QWrite("There is nothing for sale here.")
+    return
+  }
+  if len(toks) < 1 {
+    subj.QWrite("Buy what?")
+    return
+  }
+  
+  var st *shop.Stock
+  if n, err := strconv.Atoi(toks[0]); (err == nil) && (len(toks) == 1) {
+    if (n < 1) || (n > len(sp.Stock)) {
+      subj.QWrite("There is no item number %d for sale here.", n)
+      return
+    }
+    st = sp.Stock[n-1]
+  } else {
+    st = sp.Find(toks)
+    if st == nil {
+      subj.QWrite("%s doesn't sell any %q.", util.Cap(sp.Normal(name.DEF_ART)),
+                  strings.Join(toks, " "))
+      return
+    }
+  }
+  
+  if st.Count < 1 {
+    subj.QWrite("%s has none of those left.", util.Cap(sp.Normal(name.DEF_ART)))
+    return
+  }
+  if subj.coins < st.Price {
+    subj.QWrite("You can't afford %s; it costs %s.",
+                st.Proto.Normal(name.DEF_ART), thing.CoinStr(st.Price))
+    return
+  }
+  if subj.freeHands() < 1 {
+    subj.QWrite("You need a free hand to take %s.", st.Proto.Normal(name.DEF_ART))
+    return
+  }
+  if !subj.canCarry(st.Proto) {
+    subj.QWrite("You can't carry %s as well as everything else you have.",
+                st.Proto.Normal(name.DEF_ART))
+    return
+  }
+  if !scripts.Check(subj, st.Proto, sp, "buy", "from", text) {
+    return
+  }
+  
+  t := sp.Sell(st)
+  if t == nil {
+    subj.QWrite("%s can't sell you that right now.", util.Cap(sp.Normal(name.DEF_ART)))
+    return
+  }
+  subj.coins -= st.Price
+  subj.receive(t)
+  
+  m := msg.New("txt", "%s buys %s from %s.", util.Cap(subj.Normal(0)),
+               t.Normal(0), sp.Normal(name.DEF_ART))
+  m.Add(subj, "txt", "You buy %s from %s for %s.", t.Normal(0),
+        sp.Normal(name.DEF_ART), thing.CoinStr(st.Price))
+  subj.where.Place.(*room.Room).Deliver(m)
+  subj.remember(t)
+}
+
+func DoSell(pp *PlayerChar, verb string, dobj thing.Thing,
+            prep string, iobj thing.Thing, text string) {
+  
+  sp := pp.shopHere()
+  if sp == nil {
+    pp.QWrite("There is no one here to sell anything to.")
+    return
+  }
+  if dobj == nil {
+    pp.QWrite("Sell what?")
+    return
+  }
+  if !pp.Body().IsHolding(dobj) {
+    pp.QWrite("You must be holding %s to sell it.", dobj.Normal(name.DEF_ART))
+    return
+  }
+  if c, ok := dobj.(thing.Container); ok {
+    for _, s := range []byte{thing.IN, thing.ON, thing.BEHIND, thing.UNDER} {
+      if tl := c.Side(s); (tl != nil) && (len(tl.Things) > 0) {
+        pp.QWrite("You'll want to empty %s first.", dobj.Normal(name.DEF_ART))
+        return
+      }
+    }
+  }
+  
+  // SELL 5 ARROWS sells only some of a held thing.Stack.
+  var whole *thing.Stack = nil
+  if s, is_stack := dobj.(*thing.Stack); is_stack && (pp.qty > 0) {
+    if pp.qty > s.Count() {
+      pp.QWrite("You are only holding %s.", s.Normal(0))
+      return
+    }
+    if part := s.Split(pp.qty); part != nil {
+      whole, dobj = s, part
+    }
+  }
+  
+  price := sp.Offer(dobj)
+  if price < 1 {
+    pp.QWrite("%s isn't interested in %s.", util.Cap(sp.Normal(name.DEF_ART)),
+              dobj.Normal(name.DEF_ART))
+    if whole != nil {
+      whole.Merge(dobj.(*thing.Stack))
+    }
+    return
+  }
+  
+  if whole == nil {
+    pp.Release(dobj)
+  }
+  sp.Buy(dobj)
+  pp.coins += price
+  
+  m := msg.New("txt", "%s sells %s to %s.", util.Cap(pp.Normal(0)),
+               dobj.Normal(0), sp.Normal(name.DEF_ART))
+  m.Add(pp, "txt", "You sell %s to %s for %s.", dobj.Normal(0),
+        sp.Normal(name.DEF_ART), thing.CoinStr(price))
+  pp.where.Place.(*room.Room).Deliver(m)
+}
+
+func DoAppraise(pp *PlayerChar, verb string, dobj thing.Thing,
+                prep string, iobj thing.Thing, text string) {
+  
+  sp := pp.shopHere()
+  if sp == nil {
+    pp.QWrite("There is no one here to appraise anything.")
+    return
+  }
+  if dobj == nil {
+    pp.QWrite("Appraise what?")
+    return
+  }
+  
+  price := sp.Offer(dobj)
+  if price < 1 {
+    pp.QWrite("%s wouldn't give you anything for %s.",
+              util.Cap(sp.Normal(name.DEF_ART)), dobj.Normal(name.DEF_ART))
+  } else {
+    pp.QWrite("%s would give you %s for %s.", util.Cap(sp.Normal(name.DEF_ART)),
+              thing.CoinStr(price), dobj.Normal(name.DEF_ART))
+  }
+}
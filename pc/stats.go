@@ -0,0 +1,24 @@
+// stats.go
+//
+// dta5 PlayerChar attributes and skills
+//
+// updated 2026-10-19
+//
+// SCORE (or STATS) shows the PlayerChar's health, attributes, skills, and
+// experience (see dta5/stats), and any statuses they have (see
+// dta5/effect).
+//
+package pc
+
+import( "strings";
+        "dta5/effect"; "dta5/thing";
+)
+
+func DoScore(pp *PlayerChar, verb string, dobj thing.Thing,
+             prep string, iobj thing.Thing, text string) {
+  lines := pp.stats.Lines()
+  if sts := effect.Statuses(pp); len(sts) > 0 {
+    lines = append(lines, "You are " + strings.Join(sts, ", ") + ".")
+  }
+  pp.QWrite("%s\n%s", pp.Full(0), strings.Join(lines, "\n"))
+}
@@ -0,0 +1,148 @@
+// travel.go
+//
+// dta5 PlayerChar traveling to landmarks
+//
+// updated 2026-10-19
+//
+// TRAVEL <landmark> walks the PlayerChar to a landmark (see room.Landmarks)
+// by the shortest way they know of, one step every TravelDelay seconds
+// (longer if they're encumbered; see pc/carry.go); RETRACE walks them back
+// to where they last set off from. Any other command stops them where they
+// are.
+//
+package pc
+
+import( "sort"; "strings";
+        "dta5/act"; "dta5/ref"; "dta5/room"; "dta5/util";
+)
+
+// Seconds between steps while traveling.
+//
+var TravelDelay float64 = 1.0
+
+type trip struct {
+  dest *room.Room
+  name string
+}
+
+// canTake() returns whether the PlayerChar knows about (and so can take) the
+// room.Step s from r.
+//
+func (pp *PlayerChar) canTake(r *room.Room, s room.Step) bool {
+  if s.Exit != nil {
+    return pp.seesNamedExit(s.Exit)
+  }
+  return pp.seesExit(r, s.Dir)
+}
+
+// stopTrip() stops the PlayerChar traveling (if they are), and tells them
+// so.
+//
+func (pp *PlayerChar) stopTrip() {
+  if pp.trip != nil {
+    pp.trip = nil
+    pp.QWrite("You stop traveling.")
+  }
+}
+
+// setOff() starts the PlayerChar traveling to dest (called name). If
+// remember is true, RETRACE will bring them back here.
+//
+func (pp *PlayerChar) setOff(dest *room.Room, name string, remember bool) {
+  loc := pp.where.Place.(*room.Room)
+  if loc == dest {
+    pp.QWrite("You are already there.")
+    return
+  }
+  if room.Path(loc, dest, pp.canTake) == nil {
+    pp.QWrite("You can't see a way to get to %s from here.", name)
+    return
+  }
+
+  if remember {
+    pp.tripFrom = loc.Ref()
+    pp.QWrite("You set off for %s.", name)
+  } else {
+    pp.QWrite("You start retracing your steps.")
+  }
+  t := &trip{ dest: dest, name: name, }
+  pp.trip = t
+  act.Add(TravelDelay, func() error { return pp.nextStep(t) })
+}
+
+// nextStep() takes the next step of trip t (if the PlayerChar is still on
+// it) and schedules the one after.
+//
+func (pp *PlayerChar) nextStep(t *trip) error {
+  if (pp.trip != t) || (ref.Deref(pp.ref) != ref.Interface(pp)) {
+    return nil
+  }
+  loc := pp.where.Place.(*room.Room)
+  path := room.Path(loc, t.dest, pp.canTake)
+  if len(path) == 0 {
+    pp.trip = nil
+    pp.QWrite("You can't find a way onward to %s from here.", t.name)
+    return nil
+  }
+
+  s := path[0]
+  if s.Exit != nil {
+    DoMoveExit(pp, s.Exit)
+  } else {
+    DoMoveDir(pp, s.Dir)
+  }
+  if pp.trip != t {
+    return nil
+  }
+  if pp.where.Place != ref.Interface(s.To) {
+    pp.stopTrip()
+    return nil
+  }
+  if s.To == t.dest {
+    pp.trip = nil
+    pp.QWrite("You have arrived at %s.", t.name)
+    return nil
+  }
+  // An encumbered PlayerChar needs a moment more (see pc/carry.go).
+  act.Add(TravelDelay + pp.moveDelay(), func() error { return pp.nextStep(t) })
+  return nil
+}
+
+// ParseTravel handles both TRAVEL <landmark> (or just TRAVEL, to list the
+// landmarks) and RETRACE.
+//
+func ParseTravel(subj *PlayerChar, verb string, toks []string, text string) {
+  if verb == "retrace" {
+    r, ok := ref.Deref(subj.tripFrom).(*room.Room)
+    if !ok {
+      subj.QWrite("You haven't traveled anywhere to retrace your steps from.")
+      return
+    }
+    subj.setOff(r, "where you set off from", false)
+    return
+  }
+
+  if (len(toks) > 0) && (toks[0] == "to") {
+    toks = toks[1:]
+  }
+  if len(toks) == 0 {
+    names := make([]string, 0, len(room.Landmarks))
+    for n := range room.Landmarks {
+      names = append(names, n)
+    }
+    if len(names) == 0 {
+      subj.QWrite("There is nowhere you know of to travel to.")
+      return
+    }
+    sort.Strings(names)
+    subj.QWrite("You can travel to: %s.", util.EnglishList(names))
+    return
+  }
+
+  _, r := room.FindLandmark(toks)
+  if r == nil {
+    subj.QWrite("You don't know of anywhere called %q.", strings.Join(toks, " "))
+    return
+  }
+  subj.setOff(r, r.Title, true)
+}
@@ -0,0 +1,85 @@
+// vehicle.go
+//
+// dta5 PlayerChar getting on and off vehicles
+//
+// updated 2026-10-19
+//
+// BOARD (or ENTER) <vehicle> and DISEMBARK go through a vehicle.Vehicle's
+// door.Doorways; the Door is only open while the Vehicle is stopped
+// somewhere passengers can get on and off.
+//
+package pc
+
+import(
+        "dta5/door"; "dta5/msg"; "dta5/name"; "dta5/room"; "dta5/thing"; "dta5/util";
+        "dta5/vehicle";
+)
+
+// ride() takes the PlayerChar through dwy (one of the Doorways of the
+// vehicle called vname), with the given verb phrase ("board", "get off")
+// for the messages.
+//
+func (pp *PlayerChar) ride(dwy *door.Doorway, vp, vname string) {
+  loc := pp.where.Place.(*room.Room)
+  if !dwy.IsOpen() {
+    pp.QWrite("You can't %s %s until it stops to let passengers on and off.", vp, vname)
+    return
+  }
+  tgt_rm, ar_m := pp.throughDoorway(dwy)
+  if tgt_rm == nil {
+    return
+  }
+  lv_m := msg.New("txt", "%s %s %s.", util.Cap(pp.nameIn(loc)), thirdPerson(vp), vname)
+  lv_m.Add(pp, "txt", "You %s %s.", vp, vname)
+  way := func(f *PlayerChar) { f.ride(dwy, vp, vname) }
+  if pp.travel(loc, tgt_rm, lv_m, ar_m, way) {
+    pp.facing = room.NavDir(-1)
+  }
+}
+
+// type DoFunc func(*PlayerChar,
+//                  string,           verb
+//                  thing.Thing,      direct object
+//                  string,           preposition
+//                  thing.Thing,      indirect object
+//                  string)           complete command text
+
+func DoBoard(pp *PlayerChar, verb string, dobj thing.Thing,
+             prep string, iobj thing.Thing, text string) {
+  if dobj == nil {
+    pp.QWrite("Board what?")
+    return
+  }
+  v, ok := vehicle.Vehicles[dobj.Ref()]
+  if !ok {
+    pp.QWrite("You can't board %s.", dobj.Normal(name.DEF_ART))
+    return
+  }
+  if _, ok := pp.where.Place.(*room.Room); !ok || (v.At() != pp.where.Place) {
+    pp.QWrite("You can't reach %s from here.", dobj.Normal(name.DEF_ART))
+    return
+  }
+  outside, _ := v.Doorways()
+  if outside == nil {
+    pp.QWrite("There's no way onto %s.", dobj.Normal(name.DEF_ART))
+    return
+  }
+  pp.ride(outside, "board", dobj.Normal(name.DEF_ART))
+}
+
+func DoDisembark(pp *PlayerChar, verb string, dobj thing.Thing,
+                 prep string, iobj thing.Thing, text string) {
+  loc, _ := pp.where.Place.(*room.Room)
+  v := vehicle.Of(loc)
+  if v == nil {
+    pp.QWrite("You aren't aboard anything.")
+    return
+  }
+  _, inside := v.Doorways()
+  t := v.Thing()
+  if (inside == nil) || (t == nil) {
+    pp.QWrite("There's no way off from here.")
+    return
+  }
+  pp.ride(inside, "get off", t.Normal(name.DEF_ART))
+}
@@ -2,7 +2,7 @@
 //
 // dta5 PlayerChar wear/remove verbs
 //
-// updated 2017-08-18
+// updated 2026-10-19
 //
 package pc
 
@@ -49,7 +49,10 @@ func DoWear(pp *PlayerChar, verb string, dobj thing.Thing,
       }
     }
     
-    if slots_worn < can_wear {
+    if (slots_worn < can_wear) && !pp.canWear(dobj) {
+      pp.QWrite("%s is too bulky to wear with everything else you have on.",
+                util.Cap(dobj.Normal(name.DEF_ART)))
+    } else if slots_worn < can_wear {
       if rh, _ := bod.HeldIn("right_hand"); rh == dobj {
         bod.SetHeld("right_hand", nil)
       } else {
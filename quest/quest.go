@@ -0,0 +1,290 @@
+// quest.go
+//
+// dta5 quests and player flags
+//
+// updated 2026-10-19
+//
+// A Quest is a named series of Stages. Each Stage has a description (which
+// is what the player sees in their journal while on that Stage) and a list
+// of objectives; once every objective of the current Stage has been
+// achieved, the player moves on to the next Stage, and after the last,
+// the Quest is complete.
+//
+// Quests are declared in world files:
+//
+//  ["quest", "tag", "Title",
+//            [ [ "stage description", "objective", ... ], ... ] ]
+//
+// A Stage with no objectives must be passed with Advance(). The objectives
+// are just strings; scripts (see dta5/scripts and dta5/scripts/lisp) and
+// verbs report them with Achieve().
+//
+// Each player has a Log of their progress in every Quest they've started,
+// along with a set of free-form flags ("talked_to_innkeeper") for keeping
+// track of anything else worth remembering. The Log is saved with the
+// player (see pc.PlayerState).
+//
+package quest
+
+import( "fmt"; "sort";
+        "dta5/log"; "dta5/msg";
+)
+
+func log(lvl dtalog.LogLvl, fmtstr string, args ...interface{}) {
+  dtalog.Log(lvl, fmt.Sprintf("quest: " + fmtstr, args...))
+}
+
+type Stage struct {
+  Desc       string
+  Objectives []string
+}
+
+type Quest struct {
+  Tag    string
+  Title  string
+  Stages []Stage
+}
+
+// All declared Quests, by tag.
+//
+var Quests = make(map[string]*Quest)
+
+// This function prepares the package for loading the game. This should be
+// called both on initial game loading and when loading a saved game state.
+//
+func Initialize() {
+  Quests = make(map[string]*Quest)
+}
+
+// New() declares a Quest. It's meant to be called by the dta5/load package.
+//
+func New(tag, title string, stages []Stage) *Quest {
+  qp := &Quest{ Tag: tag, Title: title, Stages: stages, }
+  if _, ok := Quests[tag]; ok {
+    log(dtalog.WRN, "New(%q): replacing existing quest", tag)
+  }
+  Quests[tag] = qp
+  return qp
+}
+
+// Progress is a player's place in a single Quest: the index of the Stage
+// they're on and the objectives of that Stage they've achieved so far.
+//
+type Progress struct {
+  Stage int
+  Met   []string
+}
+
+// A Log is everything a player has done, quest-wise. It's exported (and
+// its fields are) so it can be written as JSON in the player file.
+//
+type Log struct {
+  Flags  map[string]bool
+  Active map[string]*Progress
+  Done   []string // tags of completed Quests, in order of completion
+}
+
+func NewLog() *Log {
+  return &Log{
+    Flags:  make(map[string]bool),
+    Active: make(map[string]*Progress),
+    Done:   make([]string, 0, 0),
+  }
+}
+
+// Fix() fills in any nil members (such as those of a Log read from an older
+// player file), and returns the Log (or a new one, if l is nil). Progress
+// on a Stage the Quest no longer has (because the Quest has been shortened
+// since the file was saved) is marked complete.
+//
+func (l *Log) Fix() *Log {
+  if l == nil {
+    return NewLog()
+  }
+  if l.Flags == nil {
+    l.Flags = make(map[string]bool)
+  }
+  if l.Active == nil {
+    l.Active = make(map[string]*Progress)
+  }
+  if l.Done == nil {
+    l.Done = make([]string, 0, 0)
+  }
+  for tag, p := range l.Active {
+    if p == nil {
+      p = &Progress{}
+      l.Active[tag] = p
+    }
+    if p.Stage < 0 {
+      p.Stage = 0
+    }
+    if p.Met == nil {
+      p.Met = make([]string, 0, 0)
+    }
+    if q, ok := Quests[tag]; ok && p.Stage >= len(q.Stages) {
+      log(dtalog.WRN, "(*Log) Fix(): stage %d of quest %q is past its end",
+                      p.Stage, tag)
+      delete(l.Active, tag)
+      l.Done = append(l.Done, tag)
+    }
+  }
+  return l
+}
+
+// Anything with a Log can be tracked. pc.PlayerChar is the only Tracker.
+//
+type Tracker interface {
+  msg.Messageable
+  QuestLog() *Log
+}
+
+func tell(who Tracker, fmtstr string, args ...interface{}) {
+  who.Deliver(msg.New("txt", fmtstr, args...))
+}
+
+func (l *Log) IsDone(tag string) bool {
+  for _, t := range l.Done {
+    if t == tag {
+      return true
+    }
+  }
+  return false
+}
+
+// Stage() returns the index of the Stage the player is on in the given
+// Quest, or -1 if they haven't started it (or have finished it).
+//
+func (l *Log) Stage(tag string) int {
+  if p, ok := l.Active[tag]; ok {
+    return p.Stage
+  }
+  return -1
+}
+
+// Start() puts a player on the first Stage of a Quest. It returns false
+// (and does nothing) if there's no such Quest or the player has already
+// started or finished it.
+//
+func Start(who Tracker, tag string) bool {
+  q, ok := Quests[tag]
+  if !ok {
+    log(dtalog.WRN, "Start(): no quest %q", tag)
+    return false
+  }
+  l := who.QuestLog()
+  if _, active := l.Active[tag]; active || l.IsDone(tag) {
+    return false
+  }
+  if len(q.Stages) == 0 {
+    l.Done = append(l.Done, tag)
+    return true
+  }
+  l.Active[tag] = &Progress{ Stage: 0, Met: make([]string, 0, 0), }
+  tell(who, "You have begun a new quest: %s.", q.Title)
+  return true
+}
+
+// Achieve() reports that a player has met an objective of a Quest. If it's
+// an objective of the Stage they're on, it's recorded, and if it was the
+// last one, they move on to the next Stage. It returns whether the
+// objective counted.
+//
+func Achieve(who Tracker, tag, objective string) bool {
+  q, ok := Quests[tag]
+  if !ok {
+    log(dtalog.WRN, "Achieve(): no quest %q", tag)
+    return false
+  }
+  p, ok := who.QuestLog().Active[tag]
+  if !ok {
+    return false
+  }
+  if (p.Stage < 0) || (p.Stage >= len(q.Stages)) {
+    log(dtalog.WRN, "Achieve(): stage %d of quest %q is out of range",
+                    p.Stage, tag)
+    return false
+  }
+
+  stg := q.Stages[p.Stage]
+  var wanted bool = false
+  for _, o := range stg.Objectives {
+    if o == objective {
+      wanted = true
+      break
+    }
+  }
+  if !wanted {
+    return false
+  }
+  for _, o := range p.Met {
+    if o == objective {
+      return false
+    }
+  }
+
+  p.Met = append(p.Met, objective)
+  if len(p.Met) >= len(stg.Objectives) {
+    Advance(who, tag)
+  }
+  return true
+}
+
+// Advance() moves a player on to the next Stage of a Quest regardless of
+// objectives, completing the Quest if they were on its last Stage.
+//
+func Advance(who Tracker, tag string) bool {
+  q, ok := Quests[tag]
+  if !ok {
+    log(dtalog.WRN, "Advance(): no quest %q", tag)
+    return false
+  }
+  l := who.QuestLog()
+  p, ok := l.Active[tag]
+  if !ok {
+    return false
+  }
+
+  p.Stage++
+  p.Met = make([]string, 0, 0)
+  if p.Stage >= len(q.Stages) {
+    delete(l.Active, tag)
+    l.Done = append(l.Done, tag)
+    tell(who, "You have completed a quest: %s.", q.Title)
+  } else {
+    tell(who, "Your journal has been updated.")
+  }
+  return true
+}
+
+// Journal() returns lines of text describing the player's active and
+// completed Quests.
+//
+func (l *Log) Journal() []string {
+  var lines = make([]string, 0, len(l.Active) + len(l.Done) + 2)
+
+  var tags = make([]string, 0, len(l.Active))
+  for tag := range l.Active {
+    tags = append(tags, tag)
+  }
+  sort.Strings(tags)
+  for _, tag := range tags {
+    q, ok := Quests[tag]
+    if !ok || l.Active[tag].Stage >= len(q.Stages) {
+      continue
+    }
+    lines = append(lines, fmt.Sprintf("%s: %s", q.Title, q.Stages[l.Active[tag].Stage].Desc))
+  }
+  if len(lines) == 0 {
+    lines = append(lines, "You are not currently on any quests.")
+  }
+
+  if len(l.Done) > 0 {
+    lines = append(lines, "Completed:")
+    for _, tag := range l.Done {
+      if q, ok := Quests[tag]; ok {
+        lines = append(lines, "  " + q.Title)
+      }
+    }
+  }
+  return lines
+}
@@ -0,0 +1,108 @@
+// quest_test.go
+//
+// Test suite for dta5/quest
+//
+package quest
+
+import( "encoding/json"; "testing";
+        "dta5/msg";
+)
+
+type tracker struct {
+  log  *Log
+  told int
+}
+
+func (tp *tracker) Deliver(m *msg.Message) { tp.told++ }
+func (tp *tracker) QuestLog() *Log { return tp.log }
+
+func TestProgress(t *testing.T) {
+  Initialize()
+  New("qt", "The Test", []Stage{
+    Stage{ Desc: "Find both keys.", Objectives: []string{ "key_a", "key_b", }, },
+    Stage{ Desc: "Report back.", },
+    Stage{ Desc: "Open the door.", Objectives: []string{ "door", }, },
+  })
+  who := &tracker{ log: NewLog(), }
+
+  steps := []struct {
+    do    func() bool
+    ok    bool
+    stage int
+  }{
+    { func() bool { return Achieve(who, "qt", "key_a") }, false, -1, },
+    { func() bool { return Start(who, "qt") }, true, 0, },
+    { func() bool { return Start(who, "qt") }, false, 0, },
+    { func() bool { return Achieve(who, "qt", "door") }, false, 0, },
+    { func() bool { return Achieve(who, "qt", "key_a") }, true, 0, },
+    { func() bool { return Achieve(who, "qt", "key_a") }, false, 0, },
+    { func() bool { return Achieve(who, "qt", "key_b") }, true, 1, },
+    { func() bool { return Advance(who, "qt") }, true, 2, },
+  }
+  for n, s := range steps {
+    if ok := s.do(); ok != s.ok || who.log.Stage("qt") != s.stage {
+      t.Fatalf("step %d: got %v at stage %d, want %v at stage %d",
+               n, ok, who.log.Stage("qt"), s.ok, s.stage)
+    }
+  }
+
+  // The Log is saved with the player as JSON; progress should survive.
+  who.log.Flags["met_guard"] = true
+  data, err := json.Marshal(who.log)
+  if err != nil {
+    t.Fatalf("json.Marshal(): %s", err)
+  }
+  var loaded *Log
+  if err := json.Unmarshal(data, &loaded); err != nil {
+    t.Fatalf("json.Unmarshal(): %s", err)
+  }
+  who.log = loaded.Fix()
+  if who.log.Stage("qt") != 2 || !who.log.Flags["met_guard"] {
+    t.Fatalf("after reloading: stage %d, flags %v", who.log.Stage("qt"), who.log.Flags)
+  }
+
+  if !Achieve(who, "qt", "door") || !who.log.IsDone("qt") || who.log.Stage("qt") != -1 {
+    t.Errorf("finishing after reloading: done %v, stage %d",
+             who.log.IsDone("qt"), who.log.Stage("qt"))
+  }
+  if Start(who, "qt") {
+    t.Errorf("Start() of a finished quest succeeded")
+  }
+  if who.told == 0 {
+    t.Errorf("the player was never told anything")
+  }
+}
+
+func TestFix(t *testing.T) {
+  var old *Log
+  if err := json.Unmarshal([]byte(`{"Flags":{"x":true}}`), &old); err != nil {
+    t.Fatalf("json.Unmarshal(): %s", err)
+  }
+  for _, l := range []*Log{ nil, old, } {
+    f := l.Fix()
+    if f == nil || f.Flags == nil || f.Active == nil || f.Done == nil {
+      t.Errorf("Fix(%v): got %v, want nothing nil", l, f)
+    }
+  }
+}
+
+func TestStaleStage(t *testing.T) {
+  Initialize()
+  New("qs", "The Short", []Stage{
+    Stage{ Desc: "Find the key.", Objectives: []string{ "key", }, },
+  })
+
+  // Progress saved before the Quest lost its later Stages.
+  var stale *Log
+  if err := json.Unmarshal([]byte(`{"Active":{"qs":{"Stage":3}}}`), &stale); err != nil {
+    t.Fatalf("json.Unmarshal(): %s", err)
+  }
+  who := &tracker{ log: stale, }
+  if Achieve(who, "qs", "key") {
+    t.Errorf("Achieve() at an out-of-range stage counted")
+  }
+  who.log = stale.Fix()
+  if !who.log.IsDone("qs") || who.log.Stage("qs") != -1 {
+    t.Errorf("after Fix(): done %v, stage %d", who.log.IsDone("qs"), who.log.Stage("qs"))
+  }
+}
@@ -13,7 +13,7 @@
 // string reference can have an associated map[string]interface{} for use
 // with nonstandard behavior.
 //
-// updated 2017-08-12
+// updated 2026-10-19
 //
 package ref
 
@@ -168,6 +168,14 @@ func SetData(r Interface, key string, val interface{}) {
   Data[r_str][key] = val
 }
 
+// ClearData() discards all the "arbitrary extra data" associated with r.
+//
+func ClearData(r Interface) {
+  dataLocker.Lock()
+  defer dataLocker.Unlock()
+  delete(Data, r.Ref())
+}
+
 // NilGuard() is a debugging function; its purpose should be obvious.
 //
 func NilGuard(r Interface) string {
@@ -0,0 +1,144 @@
+// path.go
+//
+// dta5 finding the way from one Room to another
+//
+// updated 2026-10-19
+//
+// The Rooms of the world, and the ways between them (in the cardinal
+// directions, by named Exits, and through Passages like door.Doorways that
+// are open), make a graph; Path() finds the shortest way through it.
+//
+// Landmarks are names for Rooms that players can travel to by name; they
+// come from the world file (see dta5/load).
+//
+package room
+
+import( "strings";
+        "dta5/ref";
+)
+
+// A Passage is something (like a door.Doorway) that a Room's exit can lead
+// through, rather than leading straight to another Room.
+//
+type Passage interface {
+  IsOpen() bool
+  LeadsTo() *Room
+}
+
+// A Step is one move from one Room to another: either in a direction, or
+// by a named Exit (in which case Dir is negative).
+//
+type Step struct {
+  Dir  NavDir
+  Exit *Exit
+  To   *Room
+}
+
+// Landmarks maps the names of landmarks to the refs of their Rooms.
+//
+var Landmarks = make(map[string]string)
+
+// This function prepares the package for loading the game. This should be
+// called both on initial game loading and when loading a saved game state.
+//
+func Initialize() {
+  Landmarks = make(map[string]string)
+}
+
+// AddLandmark() makes the Room with ref r travelable-to by name.
+//
+func AddLandmark(r, name string) {
+  Landmarks[name] = r
+}
+
+// FindLandmark() returns the name and Room of the landmark whose name
+// starts with the given (lowercase) words (or "" and nil).
+//
+func FindLandmark(toks []string) (string, *Room) {
+  want := strings.ToLower(strings.Join(toks, " "))
+  var best string
+  for name := range Landmarks {
+    if strings.HasPrefix(strings.ToLower(name), want) && ((best == "") || (name < best)) {
+      best = name
+    }
+  }
+  if best == "" {
+    return "", nil
+  }
+  r, _ := ref.Deref(Landmarks[best]).(*Room)
+  return best, r
+}
+
+// dest() returns the Room that going to x (a Room, or a Passage) ends up
+// in, or nil if x is a closed Passage (or anything else).
+//
+func dest(x ref.Interface) *Room {
+  switch d := x.(type) {
+  case *Room:
+    return d
+  case Passage:
+    if to := d.LeadsTo(); (to != nil) && d.IsOpen() {
+      return to
+    }
+  }
+  return nil
+}
+
+// Steps() returns every Step that can be taken from the Room: in the
+// cardinal directions (in order) and then by its named Exits. Ways through
+// closed Passages aren't included.
+//
+func (rp *Room) Steps() []Step {
+  steps := make([]Step, 0, len(rp.nav) + len(rp.exits))
+  for n := range rp.nav {
+    if to := dest(rp.Nav(NavDir(n))); to != nil {
+      steps = append(steps, Step{ Dir: NavDir(n), To: to, })
+    }
+  }
+  for _, e := range rp.exits {
+    if to := dest(e.Dest()); to != nil {
+      steps = append(steps, Step{ Dir: NavDir(-1), Exit: e, To: to, })
+    }
+  }
+  return steps
+}
+
+// Path() returns the shortest list of Steps from one Room to another (an
+// empty list if they're the same Room, nil if there's no way). If usable
+// isn't nil, only Steps for which it returns true (given the Room they're
+// from) are taken.
+//
+func Path(from, to *Room, usable func(*Room, Step) bool) []Step {
+  if from == to {
+    return []Step{}
+  }
+  type prev struct {
+    from *Room
+    step Step
+  }
+  came := map[*Room]prev{ from: prev{}, }
+  queue := []*Room{ from, }
+
+  for len(queue) > 0 {
+    r := queue[0]
+    queue = queue[1:]
+    for _, s := range r.Steps() {
+      if _, seen := came[s.To]; seen {
+        continue
+      }
+      if (usable != nil) && !usable(r, s) {
+        continue
+      }
+      came[s.To] = prev{ from: r, step: s, }
+      if s.To == to {
+        var path []Step
+        for x := to; x != from; x = came[x].from {
+          path = append([]Step{ came[x].step, }, path...)
+        }
+        return path
+      }
+      queue = append(queue, s.To)
+    }
+  }
+  return nil
+}
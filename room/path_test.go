@@ -0,0 +1,43 @@
+// path_test.go
+//
+// Test suite for dta5/room pathfinding
+//
+package room
+
+import( "testing"; )
+
+func TestPath(t *testing.T) {
+  a := NewRoom("pt-a", "A", "", "", "pt-b")        // a -east-> b
+  NewRoom("pt-b", "B", "", "", "", "", "pt-c")      // b -south-> c
+  c := NewRoom("pt-c", "C")
+  a.AddExit(&Exit{ Name: "the chute", Target: "pt-c", })
+  lonely := NewRoom("pt-d", "D")
+
+  p := Path(a, c, nil)
+  if (len(p) != 1) || (p[0].Exit == nil) || (p[0].To != c) {
+    t.Errorf("Path(a, c): got %v, want one step by the chute", p)
+  }
+  p = Path(a, c, func(r *Room, s Step) bool { return s.Exit == nil })
+  if (len(p) != 2) || (p[0].Dir != E) || (p[1].Dir != S) {
+    t.Errorf("Path(a, c) without exits: got %v, want east, south", p)
+  }
+  if p = Path(a, a, nil); (p == nil) || (len(p) != 0) {
+    t.Errorf("Path(a, a): got %v, want an empty path", p)
+  }
+  if p = Path(a, lonely, nil); p != nil {
+    t.Errorf("Path(a, d): got %v, want nil", p)
+  }
+}
+
+func TestRelative(t *testing.T) {
+  cases := []struct{ facing, rel, want NavDir; ok bool }{
+    { N, LEFT, W, true }, { N, RIGHT, E, true }, { SW, BACK, NE, true },
+    { NW, RIGHT, NE, true }, { UP, BACK, DOWN, true }, { UP, LEFT, UP, false },
+    { NavDir(-1), FORWARD, NavDir(-1), false },
+  }
+  for _, c := range cases {
+    if got, ok := Relative(c.facing, c.rel); (ok != c.ok) || (ok && (got != c.want)) {
+      t.Errorf("Relative(%d, %d): got %d, %v; want %d, %v", c.facing, c.rel, got, ok, c.want, c.ok)
+    }
+  }
+}
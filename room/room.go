@@ -2,12 +2,13 @@
 //
 // dta5 Room struct and methods
 //
-// updated 2017-08-13
+// updated 2026-10-19
 //
 // The Room represents a location people can be. It can be indoors or outdoors.
 // Each Room has a name (not necessarily unique), and each should have a unique
 // textual description that describes the environment. Each room has
-// navigational pointers that link it to other rooms. The Room has two lists
+// navigational pointers that link it to other rooms (in the cardinal
+// directions, and possibly by name; see Exit). The Room has two lists
 // of Things that it contains:
 //   * its Contents, which are things that explicitly appear when one LOOKs
 //     in the room; they are more or less considered to be "on the ground"
@@ -17,7 +18,7 @@
 //
 package room
 
-import(
+import( "time";
         "dta5/desc"; "dta5/msg"; "dta5/thing"; "dta5/ref"; "dta5/save";
 )
 
@@ -55,6 +56,67 @@ var NavDirNames map[NavDir]string = map[NavDir]string {
   OUT: "out",
 }
 
+// The relative directions, which mean different cardinal directions
+// depending on which way one is facing (see Relative()).
+//
+const(  FORWARD NavDir = NavDir(-1)
+        BACK    NavDir = NavDir(-2)
+        LEFT    NavDir = NavDir(-3)
+        RIGHT   NavDir = NavDir(-4)
+)
+
+// Relative() returns the cardinal direction that is rel (FORWARD, BACK,
+// LEFT, or RIGHT) for someone facing in direction facing, and false if
+// there isn't one (there's no left or right of UP, or back from OUT).
+//
+func Relative(facing, rel NavDir) (NavDir, bool) {
+  if rel >= 0 {
+    return rel, true
+  }
+  if facing < 0 {
+    return facing, false
+  }
+  if rel == FORWARD {
+    return facing, true
+  }
+  if facing <= NW {
+    switch rel {
+    case BACK:
+      return (facing + 4) % 8, true
+    case LEFT:
+      return (facing + 6) % 8, true
+    case RIGHT:
+      return (facing + 2) % 8, true
+    }
+  }
+  if rel == BACK {
+    switch facing {
+    case UP:
+      return DOWN, true
+    case DOWN:
+      return UP, true
+    }
+  }
+  return facing, false
+}
+
+// An Exit is a way out of a Room that isn't in one of the cardinal
+// directions ("climb the ladder", "enter the shop"). It's taken by the
+// command Verb + " " + Name, or "go " + Name, or any of its Aliases.
+//
+type Exit struct {
+  Name    string    // what it's called ("the ladder")
+  Verb    string    // how one takes it ("climb"); "go" if not given
+  Target  string    // ref of the Room or door.Doorway it leads to
+  Aliases []string  // other commands that take it ("climb up", "ladder")
+}
+
+// Dest() returns the Room or door.Doorway the Exit leads to, or nil.
+//
+func (e Exit) Dest() ref.Interface {
+  return ref.Deref(e.Target)
+}
+
 type Room struct {
   ref      string
   Title    string
@@ -63,6 +125,9 @@ type Room struct {
   Scenery  *thing.ThingList
   Contents *thing.ThingList
   nav      []string
+  exits    []*Exit
+  hidden   map[NavDir]int
+  dark     Darkness
 }
 
 // Creates and ref.Register()s a new Room. Generall this function is called
@@ -115,6 +180,81 @@ func (r Room) Nav(d NavDir) ref.Interface {
   }
 }
 
+// AddExit() adds a named Exit to the Room. Generally this is called by the
+// dta5/load package.
+//
+func (rp *Room) AddExit(e *Exit) {
+  if e.Verb == "" {
+    e.Verb = "go"
+  }
+  rp.exits = append(rp.exits, e)
+}
+
+// Exits() returns the Room's named Exits, in the order they were added.
+//
+func (r Room) Exits() []*Exit { return r.exits }
+
+// A hidden exit is one that has to be found (by searching) before anyone
+// can see or use it. Its concealment works like that of a
+// thing.Concealable; zero means the exit isn't hidden.
+//
+func (rp *Room) HideExit(d NavDir, concealment int) {
+  if rp.hidden == nil {
+    rp.hidden = make(map[NavDir]int)
+  }
+  if concealment == 0 {
+    delete(rp.hidden, d)
+  } else {
+    rp.hidden[d] = concealment
+  }
+}
+
+func (r Room) ExitConcealment(d NavDir) int {
+  return r.hidden[d]
+}
+
+// Returns the string that identifies a Room's exit in a particular
+// direction (for remembering who has found hidden exits).
+//
+func (r Room) ExitRef(d NavDir) string {
+  return r.ref + ":" + NavDirNames[d]
+}
+
+// How dark a Room is (when nothing in it is giving off light): never, always,
+// or only at night.
+//
+type Darkness byte
+const(  LIT        Darkness = 0
+        DARK       Darkness = 1
+        NIGHT_DARK Darkness = 2
+)
+
+// Night is any time (by the server's clock) from NightStart until DayStart.
+//
+var DayStart   int = 6
+var NightStart int = 20
+
+func IsNight() bool {
+  h := time.Now().Hour()
+  return (h >= NightStart) || (h < DayStart)
+}
+
+func (rp *Room) SetDarkness(d Darkness) { rp.dark = d }
+func (r Room) Darkness() Darkness { return r.dark }
+
+// IsDark() returns whether the Room is dark right now, not counting any
+// light sources in it (see thing.Luminous).
+//
+func (r Room) IsDark() bool {
+  switch r.dark {
+  case DARK:
+    return true
+  case NIGHT_DARK:
+    return IsNight()
+  }
+  return false
+}
+
 // Delivers a given message to all the Room's Contents.
 //
 func (r Room) Deliver(m *msg.Message) {
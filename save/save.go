@@ -2,7 +2,7 @@
 //
 // An almost-unnecessary abstraction for saving the state of the game.
 //
-// updated 2017-08-30
+// updated 2026-10-19
 //
 // A Saver is really no more than a pointer to the save game os.File and
 // a json.Encoder that writes to that file. Any saveable object (which should
@@ -10,6 +10,10 @@
 // JSON object to represent itself, and its Save() method should ask the
 // supplied Saver to encode that object.
 //
+// If Rewrite is set, every object gets passed through it on the way out;
+// dta5/load uses this to save things made from prototypes as differences
+// from them.
+//
 package save
 
 import( "encoding/json"; "os"; )
@@ -19,6 +23,11 @@ type Saver struct {
   *json.Encoder
 }
 
+// Rewrite, if it isn't nil, is given every object before it's encoded,
+// and returns what should be encoded instead (or the same object).
+//
+var Rewrite func(interface{}) interface{}
+
 type Interface interface {
   Save(Saver)
   Ref() string
@@ -34,3 +43,12 @@ func New(pth string) (*Saver, error) {
   
   return &Saver{ File: f, Encoder: ncdr, }, nil
 }
+
+// Encode() writes x (or what Rewrite turns it into) to the save file.
+//
+func (s Saver) Encode(x interface{}) error {
+  if Rewrite != nil {
+    x = Rewrite(x)
+  }
+  return s.Encoder.Encode(x)
+}
@@ -0,0 +1,309 @@
+// builtins.go
+//
+// dta5 script language core functions
+//
+// updated 2026-10-19
+//
+// The functions every script can use regardless of the game: arithmetic,
+// comparison, lists and strings. Game-specific functions are in game.go.
+//
+//  (+ n...) (- n...) (* n...) (/ n...) (mod a b)
+//  (= a b) (< a b) (> a b) (<= a b) (>= a b)
+//  (eq? a b) (not x)
+//  (list x...) (car l) (cdr l) (cons x l) (length l) (null? x)
+//  (concat x...)               all arguments as text, run together
+//  (number? x) (string? x)
+//  (random n)                  a whole number from 0 to n-1
+//
+package lisp
+
+import( "fmt"; "math/rand"; "strings";
+        "dta5/ref";
+)
+
+// Base is the Env all script Envs descend from.
+//
+var Base *Env
+
+func init() {
+  Base = NewEnv(nil)
+  var core = map[string]Builtin {
+    "+":       arith("+", func(a, b float64) float64 { return a + b }),
+    "*":       arith("*", func(a, b float64) float64 { return a * b }),
+    "-":       minus,
+    "/":       divide,
+    "mod":     mod,
+    "=":       compare("=",  func(a, b float64) bool { return a == b }),
+    "<":       compare("<",  func(a, b float64) bool { return a < b }),
+    ">":       compare(">",  func(a, b float64) bool { return a > b }),
+    "<=":      compare("<=", func(a, b float64) bool { return a <= b }),
+    ">=":      compare(">=", func(a, b float64) bool { return a >= b }),
+    "eq?":     eq,
+    "not":     not,
+    "list":    list,
+    "car":     car,
+    "cdr":     cdr,
+    "cons":    cons,
+    "length":  length,
+    "null?":   null,
+    "concat":  concat,
+    "number?": isNumber,
+    "string?": isString,
+    "random":  random,
+  }
+  for k, f := range core {
+    Base.Define(k, f)
+  }
+}
+
+func wantArgs(fname string, args []interface{}, n int) error {
+  if len(args) != n {
+    return fmt.Errorf("%s takes %d arguments, got %d", fname, n, len(args))
+  }
+  return nil
+}
+
+func num(fname string, x interface{}) (float64, error) {
+  if f, ok := x.(float64); ok {
+    return f, nil
+  }
+  return 0, fmt.Errorf("%s: %s is not a number", fname, Repr(x))
+}
+
+func arith(fname string, op func(a, b float64) float64) Builtin {
+  return func(in *Interp, args []interface{}) (interface{}, error) {
+    if len(args) == 0 {
+      return nil, fmt.Errorf("%s needs arguments", fname)
+    }
+    acc, err := num(fname, args[0])
+    if err != nil {
+      return nil, err
+    }
+    for _, a := range args[1:] {
+      n, err := num(fname, a)
+      if err != nil {
+        return nil, err
+      }
+      acc = op(acc, n)
+    }
+    return acc, nil
+  }
+}
+
+func minus(in *Interp, args []interface{}) (interface{}, error) {
+  if len(args) == 1 {
+    n, err := num("-", args[0])
+    return -n, err
+  }
+  return arith("-", func(a, b float64) float64 { return a - b })(in, args)
+}
+
+func divide(in *Interp, args []interface{}) (interface{}, error) {
+  for _, a := range args[1:] {
+    if n, ok := a.(float64); ok && n == 0 {
+      return nil, fmt.Errorf("/: division by zero")
+    }
+  }
+  return arith("/", func(a, b float64) float64 { return a / b })(in, args)
+}
+
+func mod(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("mod", args, 2); err != nil {
+    return nil, err
+  }
+  a, err := num("mod", args[0])
+  if err != nil {
+    return nil, err
+  }
+  b, err := num("mod", args[1])
+  if err != nil {
+    return nil, err
+  }
+  if int64(b) == 0 {
+    return nil, fmt.Errorf("mod: division by zero")
+  }
+  return float64(int64(a) % int64(b)), nil
+}
+
+func compare(fname string, op func(a, b float64) bool) Builtin {
+  return func(in *Interp, args []interface{}) (interface{}, error) {
+    if err := wantArgs(fname, args, 2); err != nil {
+      return nil, err
+    }
+    a, err := num(fname, args[0])
+    if err != nil {
+      return nil, err
+    }
+    b, err := num(fname, args[1])
+    if err != nil {
+      return nil, err
+    }
+    return op(a, b), nil
+  }
+}
+
+// Equal() compares two script values. Lists are equal if their elements
+// are; Things (and other ref.Interfaces) are equal if they have the same
+// ref; functions are never equal to anything.
+//
+func Equal(a, b interface{}) bool {
+  switch av := a.(type) {
+  case []interface{}:
+    bv, ok := b.([]interface{})
+    if !ok || len(av) != len(bv) {
+      return false
+    }
+    for n := range av {
+      if !Equal(av[n], bv[n]) {
+        return false
+      }
+    }
+    return true
+  case Builtin:
+    return false
+  case ref.Interface:
+    if bv, ok := b.(ref.Interface); ok {
+      return av.Ref() == bv.Ref()
+    }
+    return false
+  }
+  switch b.(type) {
+  case []interface{}, Builtin, ref.Interface:
+    return false
+  }
+  return a == b
+}
+
+func eq(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("eq?", args, 2); err != nil {
+    return nil, err
+  }
+  return Equal(args[0], args[1]), nil
+}
+
+func not(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("not", args, 1); err != nil {
+    return nil, err
+  }
+  return !Truthy(args[0]), nil
+}
+
+func list(in *Interp, args []interface{}) (interface{}, error) {
+  var lst = make([]interface{}, len(args))
+  copy(lst, args)
+  return lst, nil
+}
+
+func asList(fname string, x interface{}) ([]interface{}, error) {
+  if x == nil {
+    return []interface{}{}, nil
+  }
+  if l, ok := x.([]interface{}); ok {
+    return l, nil
+  }
+  return nil, fmt.Errorf("%s: %s is not a list", fname, Repr(x))
+}
+
+func car(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("car", args, 1); err != nil {
+    return nil, err
+  }
+  l, err := asList("car", args[0])
+  if err != nil || len(l) == 0 {
+    return nil, err
+  }
+  return l[0], nil
+}
+
+func cdr(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("cdr", args, 1); err != nil {
+    return nil, err
+  }
+  l, err := asList("cdr", args[0])
+  if err != nil || len(l) == 0 {
+    return nil, err
+  }
+  return l[1:], nil
+}
+
+func cons(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("cons", args, 2); err != nil {
+    return nil, err
+  }
+  l, err := asList("cons", args[1])
+  if err != nil {
+    return nil, err
+  }
+  var nl = make([]interface{}, 0, len(l) + 1)
+  nl = append(nl, args[0])
+  return append(nl, l...), nil
+}
+
+func length(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("length", args, 1); err != nil {
+    return nil, err
+  }
+  if s, ok := args[0].(string); ok {
+    return float64(len(s)), nil
+  }
+  l, err := asList("length", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return float64(len(l)), nil
+}
+
+func null(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("null?", args, 1); err != nil {
+    return nil, err
+  }
+  if args[0] == nil {
+    return true, nil
+  }
+  l, ok := args[0].([]interface{})
+  return ok && len(l) == 0, nil
+}
+
+func concat(in *Interp, args []interface{}) (interface{}, error) {
+  var strs = make([]string, 0, len(args))
+  var n int = 0
+  for _, a := range args {
+    s := Str(a)
+    n += len(s)
+    if n > MaxString {
+      return nil, fmt.Errorf("concat: result longer than %d bytes", MaxString)
+    }
+    strs = append(strs, s)
+  }
+  return strings.Join(strs, ""), nil
+}
+
+func isNumber(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("number?", args, 1); err != nil {
+    return nil, err
+  }
+  _, ok := args[0].(float64)
+  return ok, nil
+}
+
+func isString(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("string?", args, 1); err != nil {
+    return nil, err
+  }
+  _, ok := args[0].(string)
+  return ok, nil
+}
+
+func random(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("random", args, 1); err != nil {
+    return nil, err
+  }
+  n, err := num("random", args[0])
+  if err != nil {
+    return nil, err
+  }
+  if n < 1 {
+    return nil, fmt.Errorf("random: %s is less than 1", Str(n))
+  }
+  return float64(rand.Intn(int(n))), nil
+}
@@ -0,0 +1,444 @@
+// eval.go
+//
+// dta5 script language evaluator
+//
+// updated 2026-10-19
+//
+// Scripts are evaluated by an Interp, which counts every evaluation step
+// and gives up with an error once its limit is reached. Because the whole
+// game runs in a single goroutine, a script that loops forever would
+// otherwise hang the server; with a step limit, it just fails.
+//
+// Special forms:
+//
+//  (quote x)                       x, unevaluated
+//  (if test then [else])
+//  (when test body...)             (unless test body...)
+//  (cond (test body...)...)        an "else" test always succeeds
+//  (let ((name val)...) body...)
+//  (begin body...)
+//  (and x...)                      (or x...)
+//  (lambda (param...) body...)
+//  (define name val)               (define (name param...) body...)
+//  (set! name val)
+//
+// Only #f and nil are false; everything else is true.
+//
+package lisp
+
+import( "fmt"; "math"; "strings";
+)
+
+// The number of evaluation steps a script gets before it's stopped. This
+// applies to each run of a script (and each scheduled function) separately.
+//
+var MaxSteps int = 10000
+
+// The longest string (in bytes) a script can build with concat, so a
+// looping script can't eat all the memory there is in its MaxSteps.
+//
+var MaxString int = 4096
+
+// A Builtin is a function implemented in Go. Its arguments have already
+// been evaluated.
+//
+type Builtin func(in *Interp, args []interface{}) (interface{}, error)
+
+// A Lambda is a function defined in a script.
+//
+type Lambda struct {
+  params []Symbol
+  body   []interface{}
+  env    *Env
+}
+
+// An Env holds variable bindings; lookups that fail fall through to the
+// enclosing Env.
+//
+type Env struct {
+  vars  map[Symbol]interface{}
+  outer *Env
+}
+
+func NewEnv(outer *Env) *Env {
+  return &Env{ vars: make(map[Symbol]interface{}), outer: outer, }
+}
+
+// Define() binds a name in this Env (not in any enclosing one).
+//
+func (e *Env) Define(name string, val interface{}) {
+  e.vars[Symbol(name)] = val
+}
+
+func (e *Env) lookup(s Symbol) (interface{}, bool) {
+  for ; e != nil; e = e.outer {
+    if v, ok := e.vars[s]; ok {
+      return v, true
+    }
+  }
+  return nil, false
+}
+
+func (e *Env) set(s Symbol, val interface{}) bool {
+  for ; e != nil; e = e.outer {
+    if _, ok := e.vars[s]; ok {
+      e.vars[s] = val
+      return true
+    }
+  }
+  return false
+}
+
+// An Interp evaluates forms, keeping count of the steps taken.
+//
+type Interp struct {
+  steps int
+  limit int
+}
+
+func NewInterp() *Interp {
+  return &Interp{ limit: MaxSteps, }
+}
+
+// Truthy() reports whether a value counts as true in a test.
+//
+func Truthy(x interface{}) bool {
+  if x == nil {
+    return false
+  }
+  if b, ok := x.(bool); ok {
+    return b
+  }
+  return true
+}
+
+// Eval() evaluates a single form in the given Env.
+//
+func (in *Interp) Eval(x interface{}, e *Env) (interface{}, error) {
+  in.steps++
+  if in.steps > in.limit {
+    return nil, fmt.Errorf("step limit (%d) exceeded", in.limit)
+  }
+
+  switch v := x.(type) {
+  case Symbol:
+    if val, ok := e.lookup(v); ok {
+      return val, nil
+    }
+    return nil, fmt.Errorf("unbound symbol %q", string(v))
+  case []interface{}:
+    if len(v) == 0 {
+      return nil, nil
+    }
+    if s, ok := v[0].(Symbol); ok {
+      if sf, sok := specialForms[s]; sok {
+        return sf(in, v[1:], e)
+      }
+    }
+    f, err := in.Eval(v[0], e)
+    if err != nil {
+      return nil, err
+    }
+    var args = make([]interface{}, 0, len(v) - 1)
+    for _, a := range v[1:] {
+      av, err := in.Eval(a, e)
+      if err != nil {
+        return nil, err
+      }
+      args = append(args, av)
+    }
+    return in.Apply(f, args)
+  }
+
+  return x, nil
+}
+
+// EvalBody() evaluates a sequence of forms, returning the value of the last.
+//
+func (in *Interp) EvalBody(body []interface{}, e *Env) (interface{}, error) {
+  var val interface{}
+  var err error
+  for _, x := range body {
+    if val, err = in.Eval(x, e); err != nil {
+      return nil, err
+    }
+  }
+  return val, nil
+}
+
+// Apply() calls a Builtin or a Lambda with already-evaluated arguments.
+//
+func (in *Interp) Apply(f interface{}, args []interface{}) (interface{}, error) {
+  switch fv := f.(type) {
+  case Builtin:
+    return fv(in, args)
+  case *Lambda:
+    if len(args) != len(fv.params) {
+      return nil, fmt.Errorf("function wants %d arguments, got %d", len(fv.params), len(args))
+    }
+    ne := NewEnv(fv.env)
+    for n, p := range fv.params {
+      ne.vars[p] = args[n]
+    }
+    return in.EvalBody(fv.body, ne)
+  }
+  return nil, fmt.Errorf("%s is not a function", Repr(f))
+}
+
+type specialForm func(in *Interp, args []interface{}, e *Env) (interface{}, error)
+
+var specialForms map[Symbol]specialForm
+
+func init() {
+  specialForms = map[Symbol]specialForm {
+    "quote":  sfQuote,
+    "if":     sfIf,
+    "when":   sfWhen,
+    "unless": sfUnless,
+    "cond":   sfCond,
+    "let":    sfLet,
+    "begin":  sfBegin,
+    "and":    sfAnd,
+    "or":     sfOr,
+    "lambda": sfLambda,
+    "define": sfDefine,
+    "set!":   sfSet,
+  }
+}
+
+func sfQuote(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  if len(args) != 1 {
+    return nil, fmt.Errorf("quote takes exactly one argument")
+  }
+  return args[0], nil
+}
+
+func sfIf(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  if len(args) < 2 || len(args) > 3 {
+    return nil, fmt.Errorf("if takes two or three arguments")
+  }
+  test, err := in.Eval(args[0], e)
+  if err != nil {
+    return nil, err
+  }
+  if Truthy(test) {
+    return in.Eval(args[1], e)
+  } else if len(args) == 3 {
+    return in.Eval(args[2], e)
+  }
+  return nil, nil
+}
+
+func sfWhen(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  if len(args) < 1 {
+    return nil, fmt.Errorf("when needs a test")
+  }
+  test, err := in.Eval(args[0], e)
+  if err != nil || !Truthy(test) {
+    return nil, err
+  }
+  return in.EvalBody(args[1:], e)
+}
+
+func sfUnless(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  if len(args) < 1 {
+    return nil, fmt.Errorf("unless needs a test")
+  }
+  test, err := in.Eval(args[0], e)
+  if err != nil || Truthy(test) {
+    return nil, err
+  }
+  return in.EvalBody(args[1:], e)
+}
+
+func sfCond(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  for _, a := range args {
+    clause, ok := a.([]interface{})
+    if !ok || len(clause) == 0 {
+      return nil, fmt.Errorf("bad cond clause %s", Repr(a))
+    }
+    var test interface{} = true
+    if s, sok := clause[0].(Symbol); !sok || s != "else" {
+      var err error
+      if test, err = in.Eval(clause[0], e); err != nil {
+        return nil, err
+      }
+    }
+    if Truthy(test) {
+      return in.EvalBody(clause[1:], e)
+    }
+  }
+  return nil, nil
+}
+
+func sfLet(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  if len(args) < 1 {
+    return nil, fmt.Errorf("let needs a binding list")
+  }
+  binds, ok := args[0].([]interface{})
+  if !ok {
+    return nil, fmt.Errorf("bad let binding list %s", Repr(args[0]))
+  }
+  ne := NewEnv(e)
+  for _, b := range binds {
+    pair, pok := b.([]interface{})
+    if !pok || len(pair) != 2 {
+      return nil, fmt.Errorf("bad let binding %s", Repr(b))
+    }
+    s, sok := pair[0].(Symbol)
+    if !sok {
+      return nil, fmt.Errorf("bad let binding %s", Repr(b))
+    }
+    val, err := in.Eval(pair[1], e)
+    if err != nil {
+      return nil, err
+    }
+    ne.vars[s] = val
+  }
+  return in.EvalBody(args[1:], ne)
+}
+
+func sfBegin(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  return in.EvalBody(args, e)
+}
+
+func sfAnd(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  var val interface{} = true
+  var err error
+  for _, a := range args {
+    if val, err = in.Eval(a, e); err != nil || !Truthy(val) {
+      return val, err
+    }
+  }
+  return val, nil
+}
+
+func sfOr(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  for _, a := range args {
+    val, err := in.Eval(a, e)
+    if err != nil || Truthy(val) {
+      return val, err
+    }
+  }
+  return false, nil
+}
+
+func makeLambda(params interface{}, body []interface{}, e *Env) (*Lambda, error) {
+  plist, ok := params.([]interface{})
+  if !ok {
+    return nil, fmt.Errorf("bad parameter list %s", Repr(params))
+  }
+  var syms = make([]Symbol, 0, len(plist))
+  for _, p := range plist {
+    s, sok := p.(Symbol)
+    if !sok {
+      return nil, fmt.Errorf("bad parameter %s", Repr(p))
+    }
+    syms = append(syms, s)
+  }
+  return &Lambda{ params: syms, body: body, env: e, }, nil
+}
+
+func sfLambda(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  if len(args) < 1 {
+    return nil, fmt.Errorf("lambda needs a parameter list")
+  }
+  return makeLambda(args[0], args[1:], e)
+}
+
+func sfDefine(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  if len(args) < 1 {
+    return nil, fmt.Errorf("define needs a name")
+  }
+  switch target := args[0].(type) {
+  case Symbol:
+    if len(args) != 2 {
+      return nil, fmt.Errorf("define %s needs exactly one value", target)
+    }
+    val, err := in.Eval(args[1], e)
+    if err != nil {
+      return nil, err
+    }
+    e.vars[target] = val
+    return nil, nil
+  case []interface{}:
+    if len(target) < 1 {
+      return nil, fmt.Errorf("define needs a name")
+    }
+    s, ok := target[0].(Symbol)
+    if !ok {
+      return nil, fmt.Errorf("bad function name %s", Repr(target[0]))
+    }
+    f, err := makeLambda(target[1:], args[1:], e)
+    if err != nil {
+      return nil, err
+    }
+    e.vars[s] = f
+    return nil, nil
+  }
+  return nil, fmt.Errorf("bad define target %s", Repr(args[0]))
+}
+
+func sfSet(in *Interp, args []interface{}, e *Env) (interface{}, error) {
+  if len(args) != 2 {
+    return nil, fmt.Errorf("set! takes a name and a value")
+  }
+  s, ok := args[0].(Symbol)
+  if !ok {
+    return nil, fmt.Errorf("bad set! target %s", Repr(args[0]))
+  }
+  val, err := in.Eval(args[1], e)
+  if err != nil {
+    return nil, err
+  }
+  if !e.set(s, val) {
+    return nil, fmt.Errorf("set! of unbound symbol %q", string(s))
+  }
+  return val, nil
+}
+
+// Repr() renders a value the way it would be written in source.
+//
+func Repr(x interface{}) string {
+  switch v := x.(type) {
+  case string:
+    return fmt.Sprintf("%q", v)
+  case []interface{}:
+    var strs = make([]string, 0, len(v))
+    for _, y := range v {
+      strs = append(strs, Repr(y))
+    }
+    return "(" + strings.Join(strs, " ") + ")"
+  }
+  return Str(x)
+}
+
+// Str() renders a value as text for messaging: strings as themselves,
+// whole numbers without a decimal point, and Things by their names (see
+// game.go).
+//
+func Str(x interface{}) string {
+  switch v := x.(type) {
+  case nil:
+    return "nil"
+  case bool:
+    if v {
+      return "#t"
+    }
+    return "#f"
+  case string:
+    return v
+  case Symbol:
+    return string(v)
+  case float64:
+    if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+      return fmt.Sprintf("%d", int64(v))
+    }
+    return fmt.Sprintf("%g", v)
+  case []interface{}:
+    return Repr(v)
+  case Builtin, *Lambda:
+    return "#<function>"
+  }
+  return strX(x)
+}
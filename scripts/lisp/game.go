@@ -0,0 +1,865 @@
+// game.go
+//
+// dta5 script language game functions and loading
+//
+// updated 2026-10-19
+//
+// Script files live in the "scripts" directory of the world directory and
+// end in ".lisp". LoadDir() evaluates every such file; a file consists of
+// definitions (which are visible to every script in every file) and script
+// forms:
+//
+//  (define (greet who) (tell who "Hello."))
+//
+//  (script "band_chill"
+//    (tell subj "The band is icy cold to the touch.")
+//    #t)
+//
+// Each script form registers its body in scripts.Scripts under the given
+// tag, so it can be bound to objects just like a script written in Go:
+//
+//  ["bind", "r10-t3", "get", "band_chill"]
+//
+// When the script runs, its body is evaluated with the following variables
+// set to the arguments of the scripts.Script call: obj, subj, dobj, iobj,
+// verb, prep, text. If the body evaluates to #f, the action stops there (as
+// when a scripts.Script returns false); any other value lets it continue.
+// A script that fails (including by running out of steps; see MaxSteps) is
+// reported with scripts.Log() and the action continues.
+//
+// Room triggers (see scripts.Trigger) are written the same way with a
+// trigger form; their bodies see the variables place, subj, event and text,
+// and only a "leave" trigger's #f has any effect:
+//
+//  (trigger "vault_password"
+//    (when (contains? text "open sesame")
+//      (announce place "A hidden panel slides open.")))
+//
+//  ["bind", "r5", "say", "vault_password"]
+//
+// Game functions:
+//
+//  (name x)             x.Normal(0): "a brass key"
+//  (the x)              x.Normal(name.DEF_ART): "the brass key"
+//  (ref x)              the ref string of x
+//  (thing "ref")        the Thing (or room) with the given ref, or nil
+//  (loc x)              the Thing or room containing x
+//  (room x)             the room x is in, however deeply contained
+//  (zone x)             the tag of the zone (see dta5/zone) the room x is
+//                       in, or nil
+//  (outdoor? x), (safe? x)
+//                       the settings of that zone (#f if there isn't one)
+//  (data x "key")       ref.Data of x; Things stored by set-data! come back
+//                       as ref strings
+//  (set-data! x "key" val)
+//  (tell x text...)     delivers the text to x
+//  (announce place general (who text)...)
+//                       delivers the general text to everything in place,
+//                       except each who gets their own text instead
+//  (schedule secs fn)   calls the function fn (of no arguments) after secs
+//                       seconds
+//  (log text...)        reports via scripts.Log()
+//  (contains? text word)
+//                       whether text contains word, ignoring case
+//  (bind x verb tag [priority])
+//                       binds a script (or trigger) to x (see scripts.Bind)
+//  (unbind x verb [tag])
+//                       removes it again; without a tag, removes them all
+//  (spawn proto place [side])
+//                       puts a new copy of the thing proto in (or "on",
+//                       "behind", "under") place, and returns it (see
+//                       dta5/factory); things in rooms go on the ground
+//  (destroy x)          takes x out of the game for good
+//
+// Quest functions (see dta5/quest); who must be a player:
+//
+//  (quest-start who "quest")
+//  (quest-achieve who "quest" "objective")
+//  (quest-advance who "quest")
+//  (quest-stage who "quest")   index of the current stage, or nil
+//  (quest-done? who "quest")
+//  (flag who "flag")           whether the flag is set
+//  (set-flag! who "flag" val)
+//
+// Stat functions (see dta5/stats); who must be a player:
+//
+//  (attr who "attr")           value of an attribute
+//  (skill who "skill")         rank of a skill
+//  (skill-check who "skill" difficulty)
+//  (award who "skill" xp)
+//  (health who)
+//
+// Effect functions (see dta5/effect); who must be a player:
+//
+//  (effect who "tag" args...)  makes the effect happen to who
+//  (status? who "status")      whether who has the status
+//
+package lisp
+
+import( "fmt"; "io/ioutil"; "path/filepath"; "sort"; "strings";
+        "dta5/log";
+        "dta5/act"; "dta5/effect"; "dta5/factory"; "dta5/msg"; "dta5/name"; "dta5/quest"; "dta5/ref";
+        "dta5/room"; "dta5/scripts"; "dta5/stats"; "dta5/thing"; "dta5/zone";
+)
+
+func log(lvl dtalog.LogLvl, fmtstr string, args ...interface{}) {
+  dtalog.Log(lvl, fmt.Sprintf("scripts/lisp: " + fmtstr, args...))
+}
+
+// The file extension script files must have.
+//
+const Ext = ".lisp"
+
+// World is the Env in which script files are evaluated; definitions made
+// at the top level of any file live here.
+//
+var World *Env
+
+func init() {
+  var game = map[string]Builtin {
+    "name":      gName,
+    "the":       gThe,
+    "ref":       gRef,
+    "thing":     gThing,
+    "loc":       gLoc,
+    "room":      gRoom,
+    "zone":      gZone,
+    "outdoor?":  gOutdoor,
+    "safe?":     gSafe,
+    "data":      gData,
+    "set-data!": gSetData,
+    "tell":      gTell,
+    "announce":  gAnnounce,
+    "schedule":  gSchedule,
+    "log":       gLog,
+    "contains?": gContains,
+    "bind":      gBind,
+    "unbind":    gUnbind,
+    "spawn":     gSpawn,
+    "destroy":   gDestroy,
+    "quest-start":   gQuestStart,
+    "quest-achieve": gQuestAchieve,
+    "quest-advance": gQuestAdvance,
+    "quest-stage":   gQuestStage,
+    "quest-done?":   gQuestDone,
+    "flag":          gFlag,
+    "set-flag!":     gSetFlag,
+    "attr":          gAttr,
+    "skill":         gSkill,
+    "skill-check":   gSkillCheck,
+    "award":         gAward,
+    "health":        gHealth,
+    "effect":        gEffect,
+    "status?":       gStatus,
+  }
+  for k, f := range game {
+    Base.Define(k, f)
+  }
+  World = NewEnv(Base)
+}
+
+func strX(x interface{}) string {
+  switch v := x.(type) {
+  case name.Name:
+    return v.Normal(0)
+  case ref.Interface:
+    return v.Ref()
+  }
+  return fmt.Sprintf("%v", x)
+}
+
+// wrap() turns a Thing into a script value, making sure that a nil Thing
+// becomes nil (rather than a non-nil interface holding a nil pointer).
+//
+func wrap(t thing.Thing) interface{} {
+  if t == nil {
+    return nil
+  }
+  return t
+}
+
+func refArg(fname string, x interface{}) (ref.Interface, error) {
+  if r, ok := x.(ref.Interface); ok {
+    return r, nil
+  }
+  return nil, fmt.Errorf("%s: %s is not a thing", fname, Repr(x))
+}
+
+func thingArg(fname string, x interface{}) (thing.Thing, error) {
+  if t, ok := x.(thing.Thing); ok {
+    return t, nil
+  }
+  return nil, fmt.Errorf("%s: %s is not a thing", fname, Repr(x))
+}
+
+func gName(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("name", args, 1); err != nil {
+    return nil, err
+  }
+  if n, ok := args[0].(name.Name); ok {
+    return n.Normal(0), nil
+  }
+  if r, ok := args[0].(*room.Room); ok {
+    return r.Title, nil
+  }
+  return nil, fmt.Errorf("name: %s has no name", Repr(args[0]))
+}
+
+func gThe(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("the", args, 1); err != nil {
+    return nil, err
+  }
+  if n, ok := args[0].(name.Name); ok {
+    return n.Normal(name.DEF_ART), nil
+  }
+  return gName(in, args)
+}
+
+func gRef(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("ref", args, 1); err != nil {
+    return nil, err
+  }
+  r, err := refArg("ref", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return r.Ref(), nil
+}
+
+func gThing(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("thing", args, 1); err != nil {
+    return nil, err
+  }
+  s, ok := args[0].(string)
+  if !ok {
+    return nil, fmt.Errorf("thing: %s is not a string", Repr(args[0]))
+  }
+  if r := ref.Deref(s); r != nil {
+    return r, nil
+  }
+  return nil, nil
+}
+
+func gLoc(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("loc", args, 1); err != nil {
+    return nil, err
+  }
+  t, err := thingArg("loc", args[0])
+  if err != nil {
+    return nil, err
+  }
+  if p := t.Loc().Place; p != nil {
+    return p, nil
+  }
+  return nil, nil
+}
+
+var spawnSides = map[string]byte {
+  "in": thing.IN, "on": thing.ON, "behind": thing.BEHIND, "under": thing.UNDER,
+}
+
+func gSpawn(in *Interp, args []interface{}) (interface{}, error) {
+  if (len(args) < 2) || (len(args) > 3) {
+    return nil, fmt.Errorf("spawn takes 2 or 3 arguments, got %d", len(args))
+  }
+  proto, err := thingArg("spawn", args[0])
+  if err != nil {
+    return nil, err
+  }
+  place, err := refArg("spawn", args[1])
+  if err != nil {
+    return nil, err
+  }
+  var side byte = thing.IN
+  if _, is_room := place.(*room.Room); is_room {
+    side = room.CONTENTS
+  }
+  if len(args) > 2 {
+    var ok bool
+    if side, ok = spawnSides[Str(args[2])]; !ok {
+      return nil, fmt.Errorf("spawn: %s is not a side", Repr(args[2]))
+    }
+  }
+  t := factory.Spawn(proto, "")
+  if t == nil {
+    return nil, fmt.Errorf("spawn: %s can't be copied", Repr(args[0]))
+  }
+  if err := factory.Place(t, place, side); err != nil {
+    factory.Destroy(t)
+    return nil, fmt.Errorf("spawn: %s", err)
+  }
+  return t, nil
+}
+
+func gDestroy(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("destroy", args, 1); err != nil {
+    return nil, err
+  }
+  t, err := thingArg("destroy", args[0])
+  if err != nil {
+    return nil, err
+  }
+  if _, is_player := t.(effect.Target); is_player {
+    return nil, fmt.Errorf("destroy: %s is a player", Repr(args[0]))
+  }
+  factory.Destroy(t)
+  return true, nil
+}
+
+func gRoom(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("room", args, 1); err != nil {
+    return nil, err
+  }
+  if r, ok := args[0].(*room.Room); ok {
+    return r, nil
+  }
+  t, err := thingArg("room", args[0])
+  if err != nil {
+    return nil, err
+  }
+  if r := factory.RoomOf(t); r != nil {
+    return r, nil
+  }
+  return nil, nil
+}
+
+// zoneArg() returns the room.Room of the given argument of the named
+// function (a room, or a Thing in one).
+//
+func zoneArg(fname string, args []interface{}) (*room.Room, error) {
+  if err := wantArgs(fname, args, 1); err != nil {
+    return nil, err
+  }
+  if r, ok := args[0].(*room.Room); ok {
+    return r, nil
+  }
+  t, err := thingArg(fname, args[0])
+  if err != nil {
+    return nil, err
+  }
+  return factory.RoomOf(t), nil
+}
+
+func gZone(in *Interp, args []interface{}) (interface{}, error) {
+  r, err := zoneArg("zone", args)
+  if err != nil {
+    return nil, err
+  }
+  if z := zone.Of(r); z != nil {
+    return z.Tag, nil
+  }
+  return nil, nil
+}
+
+func gOutdoor(in *Interp, args []interface{}) (interface{}, error) {
+  r, err := zoneArg("outdoor?", args)
+  if err != nil {
+    return nil, err
+  }
+  return zone.IsOutdoor(r), nil
+}
+
+func gSafe(in *Interp, args []interface{}) (interface{}, error) {
+  r, err := zoneArg("safe?", args)
+  if err != nil {
+    return nil, err
+  }
+  return zone.IsSafe(r), nil
+}
+
+// fromData() converts a value stored in ref.Data into a script value;
+// numbers set by Go code may be any of several types, but JSON-loaded
+// ones are always float64.
+//
+func fromData(x interface{}) interface{} {
+  switch v := x.(type) {
+  case int:
+    return float64(v)
+  case int64:
+    return float64(v)
+  case float32:
+    return float64(v)
+  case []interface{}:
+    var lst = make([]interface{}, 0, len(v))
+    for _, y := range v {
+      lst = append(lst, fromData(y))
+    }
+    return lst
+  }
+  return x
+}
+
+// toData() converts a script value into something that will survive
+// being saved as JSON. Things become their ref strings.
+//
+func toData(x interface{}) (interface{}, error) {
+  switch v := x.(type) {
+  case nil, bool, float64, string:
+    return v, nil
+  case ref.Interface:
+    return v.Ref(), nil
+  case []interface{}:
+    var lst = make([]interface{}, 0, len(v))
+    for _, y := range v {
+      dy, err := toData(y)
+      if err != nil {
+        return nil, err
+      }
+      lst = append(lst, dy)
+    }
+    return lst, nil
+  }
+  return nil, fmt.Errorf("%s cannot be stored as data", Repr(x))
+}
+
+func gData(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("data", args, 2); err != nil {
+    return nil, err
+  }
+  r, err := refArg("data", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return fromData(r.Data(Str(args[1]))), nil
+}
+
+func gSetData(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("set-data!", args, 3); err != nil {
+    return nil, err
+  }
+  r, err := refArg("set-data!", args[0])
+  if err != nil {
+    return nil, err
+  }
+  val, err := toData(args[2])
+  if err != nil {
+    return nil, err
+  }
+  r.SetData(Str(args[1]), val)
+  return args[2], nil
+}
+
+func gTell(in *Interp, args []interface{}) (interface{}, error) {
+  if len(args) < 2 {
+    return nil, fmt.Errorf("tell needs a recipient and some text")
+  }
+  m, ok := args[0].(msg.Messageable)
+  if !ok {
+    return nil, fmt.Errorf("tell: %s can't receive messages", Repr(args[0]))
+  }
+  text, _ := concat(in, args[1:])
+  m.Deliver(msg.New("txt", "%s", text))
+  return true, nil
+}
+
+func gAnnounce(in *Interp, args []interface{}) (interface{}, error) {
+  if len(args) < 2 {
+    return nil, fmt.Errorf("announce needs a place and some text")
+  }
+  place, ok := args[0].(msg.Messageable)
+  if !ok {
+    return nil, fmt.Errorf("announce: %s can't receive messages", Repr(args[0]))
+  }
+  m := msg.New("txt", "%s", Str(args[1]))
+  for _, a := range args[2:] {
+    pair, pok := a.([]interface{})
+    if !pok || len(pair) != 2 {
+      return nil, fmt.Errorf("announce: %s is not a (who text) pair", Repr(a))
+    }
+    if pair[0] == nil {
+      continue
+    }
+    who, wok := pair[0].(msg.Messageable)
+    if !wok {
+      return nil, fmt.Errorf("announce: %s can't receive messages", Repr(pair[0]))
+    }
+    m.Add(who, "txt", "%s", Str(pair[1]))
+  }
+  place.Deliver(m)
+  return true, nil
+}
+
+func gSchedule(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("schedule", args, 2); err != nil {
+    return nil, err
+  }
+  secs, err := num("schedule", args[0])
+  if err != nil {
+    return nil, err
+  }
+  f, ok := args[1].(*Lambda)
+  if !ok {
+    return nil, fmt.Errorf("schedule: %s is not a script function", Repr(args[1]))
+  }
+  act.Add(secs, func() error {
+    if _, err := NewInterp().Apply(f, []interface{}{}); err != nil {
+      scripts.Log("scheduled lisp function: %s", err)
+    }
+    return nil
+  })
+  return true, nil
+}
+
+func gLog(in *Interp, args []interface{}) (interface{}, error) {
+  text, _ := concat(in, args)
+  scripts.Log("lisp: %s", text)
+  return true, nil
+}
+
+func gContains(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("contains?", args, 2); err != nil {
+    return nil, err
+  }
+  return strings.Contains(strings.ToLower(Str(args[0])),
+                          strings.ToLower(Str(args[1]))), nil
+}
+
+func gBind(in *Interp, args []interface{}) (interface{}, error) {
+  if len(args) < 3 || len(args) > 4 {
+    return nil, fmt.Errorf("bind takes three or four arguments, got %d", len(args))
+  }
+  r, err := refArg("bind", args[0])
+  if err != nil {
+    return nil, err
+  }
+  var pri float64
+  if len(args) == 4 {
+    if pri, err = num("bind", args[3]); err != nil {
+      return nil, err
+    }
+  }
+  scripts.BindPriority(r, Str(args[1]), Str(args[2]), int(pri))
+  return true, nil
+}
+
+func gUnbind(in *Interp, args []interface{}) (interface{}, error) {
+  if len(args) < 2 || len(args) > 3 {
+    return nil, fmt.Errorf("unbind takes two or three arguments, got %d", len(args))
+  }
+  r, err := refArg("unbind", args[0])
+  if err != nil {
+    return nil, err
+  }
+  var tag string
+  if len(args) == 3 {
+    tag = Str(args[2])
+  }
+  scripts.Unbind(r, Str(args[1]), tag)
+  return true, nil
+}
+
+func trackerArg(fname string, x interface{}) (quest.Tracker, error) {
+  if t, ok := x.(quest.Tracker); ok {
+    return t, nil
+  }
+  return nil, fmt.Errorf("%s: %s doesn't keep a quest log", fname, Repr(x))
+}
+
+func gQuestStart(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("quest-start", args, 2); err != nil {
+    return nil, err
+  }
+  who, err := trackerArg("quest-start", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return quest.Start(who, Str(args[1])), nil
+}
+
+func gQuestAchieve(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("quest-achieve", args, 3); err != nil {
+    return nil, err
+  }
+  who, err := trackerArg("quest-achieve", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return quest.Achieve(who, Str(args[1]), Str(args[2])), nil
+}
+
+func gQuestAdvance(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("quest-advance", args, 2); err != nil {
+    return nil, err
+  }
+  who, err := trackerArg("quest-advance", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return quest.Advance(who, Str(args[1])), nil
+}
+
+func gQuestStage(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("quest-stage", args, 2); err != nil {
+    return nil, err
+  }
+  who, err := trackerArg("quest-stage", args[0])
+  if err != nil {
+    return nil, err
+  }
+  if n := who.QuestLog().Stage(Str(args[1])); n >= 0 {
+    return float64(n), nil
+  }
+  return nil, nil
+}
+
+func gQuestDone(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("quest-done?", args, 2); err != nil {
+    return nil, err
+  }
+  who, err := trackerArg("quest-done?", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return who.QuestLog().IsDone(Str(args[1])), nil
+}
+
+func gFlag(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("flag", args, 2); err != nil {
+    return nil, err
+  }
+  who, err := trackerArg("flag", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return who.QuestLog().Flags[Str(args[1])], nil
+}
+
+func gSetFlag(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("set-flag!", args, 3); err != nil {
+    return nil, err
+  }
+  who, err := trackerArg("set-flag!", args[0])
+  if err != nil {
+    return nil, err
+  }
+  if Truthy(args[2]) {
+    who.QuestLog().Flags[Str(args[1])] = true
+  } else {
+    delete(who.QuestLog().Flags, Str(args[1]))
+  }
+  return Truthy(args[2]), nil
+}
+
+func holderArg(fname string, x interface{}) (stats.Holder, error) {
+  if h, ok := x.(stats.Holder); ok {
+    return h, nil
+  }
+  return nil, fmt.Errorf("%s: %s doesn't have stats", fname, Repr(x))
+}
+
+func gAttr(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("attr", args, 2); err != nil {
+    return nil, err
+  }
+  who, err := holderArg("attr", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return float64(who.StatSheet().Attr(Str(args[1]))), nil
+}
+
+func gSkill(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("skill", args, 2); err != nil {
+    return nil, err
+  }
+  who, err := holderArg("skill", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return float64(who.StatSheet().Rank(Str(args[1]))), nil
+}
+
+func gSkillCheck(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("skill-check", args, 3); err != nil {
+    return nil, err
+  }
+  who, err := holderArg("skill-check", args[0])
+  if err != nil {
+    return nil, err
+  }
+  diff, err := num("skill-check", args[2])
+  if err != nil {
+    return nil, err
+  }
+  return stats.Check(who, Str(args[1]), int(diff)), nil
+}
+
+func gAward(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("award", args, 3); err != nil {
+    return nil, err
+  }
+  who, err := holderArg("award", args[0])
+  if err != nil {
+    return nil, err
+  }
+  xp, err := num("award", args[2])
+  if err != nil {
+    return nil, err
+  }
+  stats.Award(who, Str(args[1]), int(xp))
+  return true, nil
+}
+
+func gHealth(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("health", args, 1); err != nil {
+    return nil, err
+  }
+  who, err := holderArg("health", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return float64(who.StatSheet().Health), nil
+}
+
+func targetArg(fname string, x interface{}) (effect.Target, error) {
+  if t, ok := x.(effect.Target); ok {
+    return t, nil
+  }
+  return nil, fmt.Errorf("%s: %s can't be affected", fname, Repr(x))
+}
+
+func gEffect(in *Interp, args []interface{}) (interface{}, error) {
+  if len(args) < 2 {
+    return nil, fmt.Errorf("effect needs at least 2 arguments, got %d", len(args))
+  }
+  who, err := targetArg("effect", args[0])
+  if err != nil {
+    return nil, err
+  }
+  tag := Str(args[1])
+  f, ok := effect.Effects[tag]
+  if !ok {
+    return nil, fmt.Errorf("effect: no effect %q", tag)
+  }
+  if err := f(who, nil, args[2:]); err != nil {
+    return nil, fmt.Errorf("effect: %q: %s", tag, err)
+  }
+  return true, nil
+}
+
+func gStatus(in *Interp, args []interface{}) (interface{}, error) {
+  if err := wantArgs("status?", args, 2); err != nil {
+    return nil, err
+  }
+  who, err := targetArg("status?", args[0])
+  if err != nil {
+    return nil, err
+  }
+  return effect.Has(who, Str(args[1])), nil
+}
+
+// makeScript() turns the body of a script form into a scripts.Script.
+//
+func makeScript(tag string, body []interface{}) scripts.Script {
+  return func(obj, subj, dobj, iobj thing.Thing,
+              verb, prep, text string) bool {
+    e := NewEnv(World)
+    e.Define("obj",  wrap(obj))
+    e.Define("subj", wrap(subj))
+    e.Define("dobj", wrap(dobj))
+    e.Define("iobj", wrap(iobj))
+    e.Define("verb", verb)
+    e.Define("prep", prep)
+    e.Define("text", text)
+
+    val, err := NewInterp().EvalBody(body, e)
+    if err != nil {
+      scripts.Log("lisp script %q (%q on %q): %s", tag, verb, obj.Ref(), err)
+      return true
+    }
+    if b, ok := val.(bool); ok && !b {
+      return false
+    }
+    return true
+  }
+}
+
+// makeTrigger() turns the body of a trigger form into a scripts.Trigger.
+//
+func makeTrigger(tag string, body []interface{}) scripts.Trigger {
+  return func(place ref.Interface, subj thing.Thing, event, text string) bool {
+    e := NewEnv(World)
+    e.Define("place", place)
+    e.Define("subj",  wrap(subj))
+    e.Define("event", event)
+    e.Define("text",  text)
+
+    val, err := NewInterp().EvalBody(body, e)
+    if err != nil {
+      scripts.Log("lisp trigger %q (%q on %q): %s", tag, event, place.Ref(), err)
+      return true
+    }
+    if b, ok := val.(bool); ok && !b {
+      return false
+    }
+    return true
+  }
+}
+
+// LoadString() evaluates script source, registering any scripts and
+// triggers it defines. The fname is used only for error messages.
+//
+func LoadString(fname, src string) error {
+  forms, err := Read(src)
+  if err != nil {
+    return fmt.Errorf("%s: %s", fname, err)
+  }
+
+  for _, f := range forms {
+    if lst, ok := f.([]interface{}); ok && len(lst) > 0 {
+      if s, sok := lst[0].(Symbol); sok && (s == "script" || s == "trigger") {
+        if len(lst) < 2 {
+          return fmt.Errorf("%s: %s form needs a tag", fname, s)
+        }
+        tag, tok := lst[1].(string)
+        if !tok {
+          return fmt.Errorf("%s: bad %s tag %s", fname, s, Repr(lst[1]))
+        }
+        if s == "script" {
+          if _, exists := scripts.Scripts[tag]; exists {
+            log(dtalog.WRN, "LoadString(%q): replacing script %q", fname, tag)
+          }
+          scripts.Scripts[tag] = makeScript(tag, lst[2:])
+        } else {
+          if _, exists := scripts.Triggers[tag]; exists {
+            log(dtalog.WRN, "LoadString(%q): replacing trigger %q", fname, tag)
+          }
+          scripts.Triggers[tag] = makeTrigger(tag, lst[2:])
+        }
+        continue
+      }
+    }
+    if _, err := NewInterp().Eval(f, World); err != nil {
+      return fmt.Errorf("%s: %s", fname, err)
+    }
+  }
+  return nil
+}
+
+// LoadDir() loads every script file in the given directory. A missing
+// directory just means the world has no scripts. It should be called
+// after any Go scripts have been registered (so tags defined here take
+// precedence) and before the world is loaded (so bindings find their tags).
+//
+func LoadDir(dir string) error {
+  paths, err := filepath.Glob(filepath.Join(dir, "*" + Ext))
+  if err != nil {
+    return err
+  }
+  sort.Strings(paths)
+  World = NewEnv(Base)
+
+  for _, p := range paths {
+    src, err := ioutil.ReadFile(p)
+    if err != nil {
+      log(dtalog.ERR, "LoadDir(): error reading %q: %s", p, err)
+      return err
+    }
+    if err = LoadString(filepath.Base(p), string(src)); err != nil {
+      log(dtalog.ERR, "LoadDir(): %s", err)
+      return err
+    }
+    log(dtalog.DBG, "LoadDir(): loaded %q", p)
+  }
+  return nil
+}
@@ -0,0 +1,65 @@
+// lisp_test.go
+//
+// testing dta5/scripts/lisp
+//
+// updated 2026-10-19
+//
+package lisp
+
+import( "testing";
+)
+
+func evalString(src string) (interface{}, error) {
+  forms, err := Read(src)
+  if err != nil {
+    return nil, err
+  }
+  return NewInterp().EvalBody(forms, NewEnv(Base))
+}
+
+var evalCases = []struct {
+  src  string
+  want interface{}
+}{
+  { `(+ 1 2 3)`, 6.0 },
+  { `(- 5)`, -5.0 },
+  { `(if (< 1 2) "yes" "no")`, "yes" },
+  { `(concat "a" 1 " " #t)`, "a1 #t" },
+  { `(let ((x 2) (y 3)) (* x y))`, 6.0 },
+  { `(define (sq x) (* x x)) (sq 7)`, 49.0 },
+  { `(define n 0) (set! n (+ n 1)) n`, 1.0 },
+  { `(cond ((= 1 2) 'a) (else 'b))`, Symbol("b") },
+  { `(car (cdr '(1 2 3)))`, 2.0 },
+  { `(length (cons 0 (list 1 2)))`, 3.0 },
+  { `(and 1 nil 2)`, nil },
+  { `(or #f "x")`, "x" },
+  { `(eq? '(1 "a") (list 1 "a"))`, true },
+  { "; comment\n\"q\\\"uote\"", "q\"uote" },
+}
+
+func TestEval(t *testing.T) {
+  for _, c := range evalCases {
+    got, err := evalString(c.src)
+    if err != nil {
+      t.Errorf("%s: error: %s", c.src, err)
+    } else if !Equal(got, c.want) {
+      t.Errorf("%s: got %s, want %s", c.src, Repr(got), Repr(c.want))
+    }
+  }
+}
+
+func TestErrors(t *testing.T) {
+  var bad = []string{
+    `(+ 1`,
+    `(undefined-thing)`,
+    `(/ 1 0)`,
+    `(car 5)`,
+    `(define (loop) (loop)) (loop)`,
+    `(define (dbl s n) (if (= n 0) s (dbl (concat s s) (- n 1)))) (dbl "x" 13)`,
+  }
+  for _, src := range bad {
+    if _, err := evalString(src); err == nil {
+      t.Errorf("%s: expected an error", src)
+    }
+  }
+}
@@ -0,0 +1,174 @@
+// read.go
+//
+// dta5 script language reader
+//
+// updated 2026-10-19
+//
+// Turns script source text into the values the evaluator (see eval.go)
+// operates on. The syntax is a small Lisp:
+//
+//  ; comments run to the end of the line
+//  (a list of things)
+//  'x           shorthand for (quote x)
+//  "a string"   with \" \\ \n and \t escapes
+//  12 -3 0.5    numbers (all numbers are float64)
+//  #t #f nil    true, false, and the empty value
+//
+// Everything else is a Symbol.
+//
+package lisp
+
+import( "fmt"; "strconv"; "strings"; "unicode";
+)
+
+// A Symbol is a bare word in script source; it evaluates to whatever it's
+// bound to in the current Env.
+//
+type Symbol string
+
+type reader struct {
+  src  []rune
+  pos  int
+  line int
+}
+
+// Read() parses all the top-level forms out of src.
+//
+func Read(src string) ([]interface{}, error) {
+  rd := reader{ src: []rune(src), line: 1, }
+  var forms = make([]interface{}, 0)
+  for {
+    rd.skip()
+    if rd.pos >= len(rd.src) {
+      return forms, nil
+    }
+    x, err := rd.form()
+    if err != nil {
+      return forms, err
+    }
+    forms = append(forms, x)
+  }
+}
+
+// skip() advances past whitespace and comments.
+//
+func (rd *reader) skip() {
+  for rd.pos < len(rd.src) {
+    c := rd.src[rd.pos]
+    if c == ';' {
+      for rd.pos < len(rd.src) && rd.src[rd.pos] != '\n' {
+        rd.pos++
+      }
+    } else if unicode.IsSpace(c) {
+      if c == '\n' {
+        rd.line++
+      }
+      rd.pos++
+    } else {
+      return
+    }
+  }
+}
+
+func (rd *reader) errorf(fmtstr string, args ...interface{}) error {
+  return fmt.Errorf("line %d: %s", rd.line, fmt.Sprintf(fmtstr, args...))
+}
+
+func (rd *reader) form() (interface{}, error) {
+  rd.skip()
+  if rd.pos >= len(rd.src) {
+    return nil, rd.errorf("unexpected end of input")
+  }
+
+  switch c := rd.src[rd.pos]; c {
+  case '(':
+    rd.pos++
+    var lst = make([]interface{}, 0)
+    for {
+      rd.skip()
+      if rd.pos >= len(rd.src) {
+        return nil, rd.errorf("unclosed list")
+      }
+      if rd.src[rd.pos] == ')' {
+        rd.pos++
+        return lst, nil
+      }
+      x, err := rd.form()
+      if err != nil {
+        return nil, err
+      }
+      lst = append(lst, x)
+    }
+  case ')':
+    return nil, rd.errorf("unexpected ')'")
+  case '\'':
+    rd.pos++
+    x, err := rd.form()
+    if err != nil {
+      return nil, err
+    }
+    return []interface{}{ Symbol("quote"), x }, nil
+  case '"':
+    return rd.str()
+  }
+
+  return rd.atom()
+}
+
+func (rd *reader) str() (interface{}, error) {
+  var sb strings.Builder
+  rd.pos++
+  for rd.pos < len(rd.src) {
+    c := rd.src[rd.pos]
+    rd.pos++
+    switch c {
+    case '"':
+      return sb.String(), nil
+    case '\\':
+      if rd.pos >= len(rd.src) {
+        return nil, rd.errorf("unterminated string")
+      }
+      e := rd.src[rd.pos]
+      rd.pos++
+      switch e {
+      case 'n':
+        sb.WriteRune('\n')
+      case 't':
+        sb.WriteRune('\t')
+      default:
+        sb.WriteRune(e)
+      }
+    case '\n':
+      rd.line++
+      sb.WriteRune(c)
+    default:
+      sb.WriteRune(c)
+    }
+  }
+  return nil, rd.errorf("unterminated string")
+}
+
+func (rd *reader) atom() (interface{}, error) {
+  start := rd.pos
+  for rd.pos < len(rd.src) {
+    c := rd.src[rd.pos]
+    if unicode.IsSpace(c) || c == '(' || c == ')' || c == '"' || c == ';' {
+      break
+    }
+    rd.pos++
+  }
+  tok := string(rd.src[start:rd.pos])
+
+  switch tok {
+  case "#t":
+    return true, nil
+  case "#f":
+    return false, nil
+  case "nil":
+    return nil, nil
+  }
+  if f, err := strconv.ParseFloat(tok, 64); err == nil {
+    return f, nil
+  }
+  return Symbol(tok), nil
+}
@@ -13,7 +13,7 @@
 package more
 
 import(
-        "dta5/msg"; "dta5/thing"
+        "dta5/msg"; "dta5/quest"; "dta5/thing"
         "dta5/scripts"
 )
 
@@ -51,4 +51,29 @@ func Initialize() {
     subj.Deliver(msg.New("txt", mesg))
     return false
   }
+  
+  // Achieves a quest objective (see dta5/quest) for the subject. The object
+  // should have data "quest_objective" set to [ "quest_tag", "objective" ].
+  // This makes most sense bound to an "after:" verb (see dta5/scripts).
+  scripts.Scripts["quest_objective"] = func(obj, subj, dobj, iobj thing.Thing,
+                                            verb, prep, text string) bool {
+    who, ok := subj.(quest.Tracker)
+    if !ok {
+      return true
+    }
+    dat, ok := obj.Data("quest_objective").([]interface{})
+    if !ok || len(dat) != 2 {
+      scripts.Log("quest_objective(%q): bad quest_objective data", obj.Ref())
+      return true
+    }
+    tag, tok := dat[0].(string)
+    objective, ook := dat[1].(string)
+    if !(tok && ook) {
+      scripts.Log("quest_objective(%q): bad quest_objective data", obj.Ref())
+      return true
+    }
+    
+    quest.Achieve(who, tag, objective)
+    return true
+  }
 }
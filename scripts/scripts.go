@@ -3,7 +3,7 @@
 // The dta5/scripts package provides a framework for specifying per-object
 // custom behavior.
 //
-// updated 2017-08-29
+// updated 2026-10-19
 //
 // Each thing.Thing behaves in a predictable way to being the object of any
 // given verb, eg., a thing.Container that IsToggleable() will open or close
@@ -28,12 +28,14 @@
 // (see the Script type, below), but can be defined anywhere that imports
 // this package. By default, there is a dta5/scripts/more package where I have
 // collected the majority of the custom scripts that I have written, but you
-// can put them anywhere.
+// can put them anywhere. Scripts can also be written without recompiling,
+// in the small embedded language of the dta5/scripts/lisp package; those
+// live in the "scripts" directory of the world directory.
 //
 package scripts
 
-import( "fmt";
-        "dta5/log"; "dta5/ref"; "dta5/save"; "dta5/thing";
+import( "fmt"; "strings";
+        "dta5/log"; "dta5/act"; "dta5/ref"; "dta5/save"; "dta5/thing";
 )
 
 func log(lvl dtalog.LogLvl, fmtstr string, args ...interface{}) {
@@ -78,82 +80,317 @@ type Script func(obj, subj, dobj, iobj thing.Thing,
 //
 var Scripts = make(map[string]Script)
 //
-// defined in pc/lock.go
-//
-//   * "locked_script"
-//     Prevents a player from opening a thing.Openable and informs him that
-//     it's locked, unless the associated data value "locked_script_unlocked"
-//     is set to true.
-//
-//   * "lock_unlock_script"
-//     Designates a thing.Thing to be a "key" that allows a thing.Openable
-//     under the influence of the "locked_script" to be UNLOCKed by it.
-//
 // defined in pc/open.go
 //
 //   * "auto_close_script"
 //     Causes a thing.Openable to shut automatically after it has been opened
 //    (after a specified delay).
 
+// A Binding is one link in the chain of scripts bound to an object and
+// verb. Bindings with lower Priority values run first; Bindings with equal
+// Priority run in the order they were bound.
+//
+type Binding struct {
+  Tag      string
+  Priority int
+}
+
+// A verb of Wildcard binds a script to every verb (but not to After()
+// hooks, which have their own wildcard, AfterPrefix + Wildcard). For a
+// given verb, the wildcard chain is merged with the verb's own chain by
+// priority; at equal priority, the verb's own Bindings run first.
+//
+const Wildcard = "*"
+
 // Bindings stores the bindings between objects, verbs, and scripts.
 // The keys to the map are the ref index strings of the objects to which
 // scripts are bound. Each value in the map is either
 //   * nil (that is, the zero value) if the object has no associated scripts
-//   * another map that maps verbs with the identifying strings of the
-//     scripts associated with those verbs.
+//   * another map that maps verbs with the chains of Bindings associated
+//     with those verbs, in the order they should run.
 // Outside of saving and loading, this map should never be accessed outside
-// of using the Bind() and Check() functions (below).
+// of using the Bind(), Unbind(), and Check() functions (below).
+//
+var Bindings = make(map[string]map[string][]Binding)
+
+// This function prepares the package for loading the game, forgetting all
+// Bindings and Timers (but not the Scripts and Triggers they refer to). This
+// should be called both on initial game loading and when loading a saved
+// game state.
+//
+func Initialize() {
+  Bindings = make(map[string]map[string][]Binding)
+  Timers = make(map[string]float64)
+}
+
+// chain() returns the Bindings that apply to the given verb for the object
+// with the given ref, including any bound to the wildcard, in order.
 //
-var Bindings = make(map[string]map[string]string)
+func chain(r, verb string) []Binding {
+  vmap := Bindings[r]
+  if vmap == nil {
+    return nil
+  }
+  var wild string = Wildcard
+  if strings.HasPrefix(verb, AfterPrefix) {
+    wild = AfterPrefix + Wildcard
+  }
+  own, all := vmap[verb], vmap[wild]
+  if len(all) == 0 || verb == wild {
+    return own
+  }
 
-// Check() determines if an object has a script associated with a particular
-// verb and calls that script if it does. This function should be called
-// by _all_ code that performs "game actions" on things.
+  var merged = make([]Binding, 0, len(own) + len(all))
+  for len(own) > 0 && len(all) > 0 {
+    if all[0].Priority < own[0].Priority {
+      merged, all = append(merged, all[0]), all[1:]
+    } else {
+      merged, own = append(merged, own[0]), own[1:]
+    }
+  }
+  merged = append(merged, own...)
+  return append(merged, all...)
+}
+
+// Check() determines if an object has scripts associated with a particular
+// verb and calls them, in order, if it does; the first to return false
+// stops the action (and the rest of the chain). This function should be called
+// by _all_ code that performs "game actions" on things. The indirect object
+// is checked first, then the direct object, then the subject itself (so
+// a script bound to a character can affect everything it does).
 //
 func Check(subj, dobj, iobj thing.Thing, verb, prep, text string) bool {
-  
-  if iobj != nil {
-    if f, sok := Scripts[Bindings[iobj.Ref()][verb]]; sok {
-      cont := f(iobj, subj, dobj, iobj, verb, prep, text)
-      if !cont {
-        return false
+  for _, obj := range involved(subj, dobj, iobj) {
+    for _, b := range chain(obj.Ref(), verb) {
+      if f, sok := Scripts[b.Tag]; sok {
+        cont := f(obj, subj, dobj, iobj, verb, prep, text)
+        if !cont {
+          return false
+        }
+      }
+    }
+  }
+  return true
+}
+
+// The verb of a binding that should be run by After() rather than Check()
+// is the action's verb with this prefix, eg, "after:get".
+//
+const AfterPrefix = "after:"
+
+// After() runs any scripts bound to the objects of an action (in the same
+// order as Check()) with the verb AfterPrefix + verb. It should be called
+// once the action has been carried out; the Scripts' return values are
+// ignored, since it's too late to stop anything. (The action has been
+// _attempted_, anyway; the code that performs a verb doesn't report whether
+// it succeeded, so an After script that cares should check for itself.)
+//
+func After(subj, dobj, iobj thing.Thing, verb, prep, text string) {
+  for _, obj := range involved(subj, dobj, iobj) {
+    for _, b := range chain(obj.Ref(), AfterPrefix + verb) {
+      if f, sok := Scripts[b.Tag]; sok {
+        f(obj, subj, dobj, iobj, verb, prep, text)
       }
     }
   }
-  
-  if dobj != nil {
-    if f, sok := Scripts[Bindings[dobj.Ref()][verb]]; sok {
-      cont := f(dobj, subj, dobj, iobj, verb, prep, text)
-      if !cont {
-        return false
+}
+
+// involved() lists the non-nil participants in an action, each once, in
+// the order their scripts should be checked.
+//
+func involved(subj, dobj, iobj thing.Thing) []thing.Thing {
+  var objs = make([]thing.Thing, 0, 3)
+  for _, t := range []thing.Thing{ iobj, dobj, subj, } {
+    if t == nil {
+      continue
+    }
+    var dup bool = false
+    for _, o := range objs {
+      if o == t {
+        dup = true
+        break
       }
     }
+    if !dup {
+      objs = append(objs, t)
+    }
   }
-  
-  return true
+  return objs
+}
+
+// Rooms aren't Things, so they can't have Scripts bound to them; instead
+// they have Triggers, which are bound the same way (with Bind(), using one
+// of the event names below in place of a verb) and fired by Fire().
+//
+//  place: the room (or other ref.Interface) the Trigger is bound to
+//  subj:  the Thing that caused the event (nil for TIMER events)
+//  event: the event name
+//  text:  for SAY, the words spoken; otherwise empty
+//
+// Only the return value of a LEAVE Trigger matters: if false, the subject
+// doesn't leave (and the Trigger should explain why).
+//
+type Trigger func(place ref.Interface, subj thing.Thing,
+                  event, text string) bool
+
+// Triggers associates each Trigger with its identifying string, as
+// Scripts does for Scripts.
+//
+var Triggers = make(map[string]Trigger)
+
+const(  ENTER = "enter"   // after someone arrives
+        LEAVE = "leave"   // before someone leaves
+        SAY   = "say"     // after someone speaks
+        TIMER = "timer"   // periodically (see SetTimer())
+)
+
+// Fire() calls the Triggers bound to the given place for the given event,
+// in order. Every Trigger in the chain runs (an event, unlike an action,
+// can't be half-stopped); the result is false if any of them returned false.
+//
+func Fire(place ref.Interface, event string, subj thing.Thing, text string) bool {
+  if place == nil {
+    return true
+  }
+  var cont bool = true
+  for _, b := range chain(place.Ref(), event) {
+    if f, ok := Triggers[b.Tag]; ok {
+      cont = f(place, subj, event, text) && cont
+    }
+  }
+  return cont
+}
+
+// Timers holds the interval (in seconds) at which each place with a TIMER
+// Trigger fires it, keyed by the place's ref.
+//
+var Timers = make(map[string]float64)
+
+// SetTimer() makes the given place fire its TIMER Trigger every secs
+// seconds, once ArmTimers() is called.
+//
+func SetTimer(place ref.Interface, secs float64) {
+  if secs <= 0 {
+    log(dtalog.WRN, "SetTimer(%q, %f): interval must be positive", place.Ref(), secs)
+    return
+  }
+  Timers[place.Ref()] = secs
+}
+
+// ArmTimers() schedules the first firing of every TIMER; each one
+// reschedules itself. It should be called once the world is loaded and
+// the dta5/act queue is running.
+//
+func ArmTimers() {
+  for r, secs := range Timers {
+    armTimer(r, secs)
+  }
+}
+
+func armTimer(r string, secs float64) {
+  act.Add(secs, func() error {
+    place := ref.Deref(r)
+    if place == nil || Timers[r] != secs {
+      return nil
+    }
+    Fire(place, TIMER, nil, "")
+    armTimer(r, secs)
+    return nil
+  })
 }
 
 // Bind() associates (verb, Script) pairs with objects. The ftag parameter
 // is the identifying string of the given Script function one desires to bind.
+// Triggers are bound to places the same way, with an event name as the verb.
+// The new Binding goes at the end of the chain with priority 0.
 //
 func Bind(obj ref.Interface, verb string, ftag string) {
-  if _, ok := Scripts[ftag]; ok {
-    if _, mok := Bindings[obj.Ref()]; !mok {
-      Bindings[obj.Ref()] = make(map[string]string)
-    }
-    Bindings[obj.Ref()][verb] = ftag
-  } else {
+  BindPriority(obj, verb, ftag, 0)
+}
+
+// BindPriority() is Bind() with a specified priority (see Binding). Binding
+// a tag that's already bound to the same object and verb just moves it to
+// its new place in the chain.
+//
+func BindPriority(obj ref.Interface, verb, ftag string, priority int) {
+  _, is_script := Scripts[ftag]
+  _, is_trigger := Triggers[ftag]
+  if !(is_script || is_trigger) {
     log(dtalog.WRN, "Bind(%q, %q, %q): %q is not a script tag", obj.Ref(), verb, ftag)
+    return
+  }
+
+  r := obj.Ref()
+  if _, mok := Bindings[r]; !mok {
+    Bindings[r] = make(map[string][]Binding)
+  }
+  var chn = without(Bindings[r][verb], ftag)
+  var n int = len(chn)
+  for n > 0 && chn[n-1].Priority > priority {
+    n--
+  }
+  chn = append(chn, Binding{})
+  copy(chn[n+1:], chn[n:])
+  chn[n] = Binding{ Tag: ftag, Priority: priority, }
+  Bindings[r][verb] = chn
+}
+
+// Unbind() removes the script with the given tag from the object's chain
+// for the given verb. If ftag is empty, it removes the whole chain.
+//
+func Unbind(obj ref.Interface, verb, ftag string) {
+  r := obj.Ref()
+  vmap, ok := Bindings[r]
+  if !ok {
+    return
+  }
+  if ftag == "" {
+    delete(vmap, verb)
+  } else if chn := without(vmap[verb], ftag); len(chn) > 0 {
+    vmap[verb] = chn
+  } else {
+    delete(vmap, verb)
+  }
+  if len(vmap) == 0 {
+    delete(Bindings, r)
   }
 }
 
-// This is called during the saving process to save all script bindings.
+// UnbindAll() removes every script and trigger bound to the object (and
+// its timer, if it has one), for when it's leaving the game for good.
+//
+func UnbindAll(obj ref.Interface) {
+  delete(Bindings, obj.Ref())
+  delete(Timers, obj.Ref())
+}
+
+// without() returns a copy of the chain with any Binding of the given tag
+// removed.
+//
+func without(chn []Binding, ftag string) []Binding {
+  var nchn = make([]Binding, 0, len(chn) + 1)
+  for _, b := range chn {
+    if b.Tag != ftag {
+      nchn = append(nchn, b)
+    }
+  }
+  return nchn
+}
+
+// This is called during the saving process to save all script bindings
+// (and timers). Each chain is written in order, so loading the records back
+// in order, after Initialize(), rebuilds it exactly.
 //
 func SaveBindings(s save.Saver) {
   for t_ref, vmap := range Bindings {
-    for verb, script_tag := range vmap {
-      data := []interface{}{"script", t_ref, verb, script_tag}
-      s.Encode(data)
+    for verb, chn := range vmap {
+      for _, b := range chn {
+        data := []interface{}{"script", t_ref, verb, b.Tag, b.Priority}
+        s.Encode(data)
+      }
     }
   }
+  for t_ref, secs := range Timers {
+    s.Encode([]interface{}{"timer", t_ref, secs})
+  }
 }
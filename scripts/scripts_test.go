@@ -0,0 +1,73 @@
+// scripts_test.go
+//
+// Test suite for dta5/scripts
+//
+package scripts
+
+import( "strings"; "testing";
+        "dta5/thing";
+)
+
+func TestChains(t *testing.T) {
+  Initialize()
+  var ran []string
+  for _, tag := range []string{ "a", "b", "c", "w", "x", } {
+    tag := tag
+    Scripts["st_" + tag] = func(obj, subj, dobj, iobj thing.Thing,
+                                verb, prep, text string) bool {
+      ran = append(ran, tag)
+      return tag != "x"
+    }
+  }
+  obj := thing.NewItem("st-obj", "a widget", "", false, 1.0, 1.0)
+  run := func(verb string) string {
+    ran = ran[:0]
+    Check(nil, obj, nil, verb, "", "")
+    return strings.Join(ran, "")
+  }
+
+  tests := []struct {
+    desc string
+    do   func()
+    verb string
+    want string
+  }{
+    { "binding order", func() {
+        Bind(obj, "get", "st_a")
+        Bind(obj, "get", "st_b")
+      }, "get", "ab", },
+    { "priority", func() {
+        BindPriority(obj, "get", "st_c", -1)
+      }, "get", "cab", },
+    { "rebinding moves", func() {
+        BindPriority(obj, "get", "st_a", 5)
+      }, "get", "cba", },
+    { "wildcard merge", func() {
+        BindPriority(obj, Wildcard, "st_w", 0)
+      }, "get", "cbwa", },
+    { "wildcard alone", func() {}, "drop", "w", },
+    { "wildcard not after", func() {}, AfterPrefix + "get", "", },
+    { "false stops the chain", func() {
+        BindPriority(obj, "get", "st_x", -2)
+      }, "get", "x", },
+    { "Unbind", func() {
+        Unbind(obj, "get", "st_x")
+        Unbind(obj, "get", "st_b")
+      }, "get", "cwa", },
+    { "Unbind a whole chain", func() {
+        Unbind(obj, "get", "")
+      }, "get", "w", },
+    { "Unbind the last", func() {
+        Unbind(obj, Wildcard, "st_w")
+      }, "get", "", },
+  }
+  for _, tc := range tests {
+    tc.do()
+    if got := run(tc.verb); got != tc.want {
+      t.Errorf("%s: %s ran %q, want %q", tc.desc, tc.verb, got, tc.want)
+    }
+  }
+  if _, ok := Bindings[obj.Ref()]; ok {
+    t.Errorf("Unbind() of everything left %v", Bindings[obj.Ref()])
+  }
+}
@@ -0,0 +1,188 @@
+// shop.go
+//
+// dta5 shops
+//
+// updated 2026-10-19
+//
+// A Shop is an immovable thing (a counter, a market stall, a shopkeeper
+// standing behind one) that sells copies of the prototype things in its
+// stock list and buys things from PlayerChars for coins (see thing.Coins).
+//
+// Each Stock entry has a price, a number of copies currently on hand, and
+// a maximum number. When copies are sold, the Shop schedules a restock (on
+// the dta5/act queue) that brings one more copy of each depleted entry back
+// every RestockDelay seconds until everything is back up to its maximum.
+//
+// Prototypes are ordinary Things that are loaded but aren't anywhere in the
+// world; the copies sold are made with thing.Clone() and share their
+// prototype's description. Things bought from PlayerChars are destroyed.
+//
+// In world and save files, a Shop looks like
+//
+//  ["shop", "ref", "artAdjNoun", "prepPhrase", restock_secs, buy_rate,
+//           serial, [ ["proto_ref", price, count, max ]... ] ]
+//
+// where buy_rate is the fraction of a thing's value the Shop pays for it,
+// and serial is the number used to make the next copy's reference string
+// (just use 0 in world files).
+//
+package shop
+
+import( "fmt"; "math";
+        "dta5/act"; "dta5/factory"; "dta5/log"; "dta5/ref"; "dta5/save"; "dta5/thing";
+)
+
+func log(lvl dtalog.LogLvl, fmtstr string, args ...interface{}) {
+  dtalog.Log(lvl, fmt.Sprintf("shop: " + fmtstr, args...))
+}
+
+// A Stock entry is one kind of thing a Shop sells.
+//
+type Stock struct {
+  Proto thing.Thing
+  Price int
+  Count int
+  Max   int
+}
+
+type Shop struct {
+  thing.Item
+  Stock        []*Stock
+  BuyRate      float64
+  RestockDelay float64
+  serial       int
+  restocking   bool
+}
+
+// All loaded Shops, by reference string, so they can be Arm()ed after
+// loading.
+//
+var Shops = make(map[string]*Shop)
+
+// This function prepares the package for loading the game. This should be
+// called both on initial game loading and when loading a saved game state.
+//
+func Initialize() {
+  Shops = make(map[string]*Shop)
+}
+
+// Creates, ref.Register()s, and returns a new Shop with nothing in stock.
+//
+func New(nref, artAdjNoun, prep string, restockDelay, buyRate float64,
+         serial int) *Shop {
+  ni := thing.NewItem(nref, artAdjNoun, prep, false, thing.VT_UNLTD, thing.VT_UNLTD)
+  nsp := &Shop{
+    Item:         *ni,
+    Stock:        make([]*Stock, 0, 0),
+    BuyRate:      buyRate,
+    RestockDelay: restockDelay,
+    serial:       serial,
+  }
+  ref.Reregister(nsp)
+  Shops[nref] = nsp
+  return nsp
+}
+
+// AddStock() adds an entry to the Shop's stock list.
+//
+func (sp *Shop) AddStock(proto thing.Thing, price, count, max int) {
+  sp.Stock = append(sp.Stock, &Stock{ Proto: proto, Price: price,
+                                      Count: count, Max: max, })
+}
+
+// Find() returns the first entry in the Shop's stock list whose prototype's
+// name matches toks, preferring entries that aren't sold out, or nil if
+// none matches.
+//
+func (sp *Shop) Find(toks []string) *Stock {
+  var sold_out *Stock = nil
+  for _, st := range sp.Stock {
+    if st.Proto.Match(toks) {
+      if st.Count > 0 {
+        return st
+      } else if sold_out == nil {
+        sold_out = st
+      }
+    }
+  }
+  return sold_out
+}
+
+// Sell() makes and returns a new copy of the entry's prototype, taking one
+// off the count, or returns nil if the entry is sold out (or the prototype
+// can't be copied).
+//
+func (sp *Shop) Sell(st *Stock) thing.Thing {
+  if st.Count < 1 {
+    return nil
+  }
+  nref := fmt.Sprintf("%s-s%d", sp.Ref(), sp.serial)
+  t := thing.Clone(st.Proto, nref)
+  if t == nil {
+    log(dtalog.ERR, "(*Shop %q) Sell(): unable to copy %q", sp.Ref(), st.Proto.Ref())
+    return nil
+  }
+  sp.serial++
+  st.Count--
+  sp.Arm()
+  return t
+}
+
+// Offer() returns how much the Shop will pay for t.
+//
+func (sp *Shop) Offer(t thing.Thing) int {
+  v, ok := t.(thing.Valued)
+  if !ok {
+    return 0
+  }
+  return int(math.Floor(float64(v.Value()) * sp.BuyRate))
+}
+
+// Buy() takes t out of the world for good (see factory.Destroy()).
+//
+func (sp *Shop) Buy(t thing.Thing) {
+  factory.Destroy(t)
+}
+
+// Arm() schedules a restock if anything in the Shop's stock is below its
+// maximum and one isn't scheduled already.
+//
+func (sp *Shop) Arm() {
+  if sp.restocking || (sp.RestockDelay <= 0) {
+    return
+  }
+  for _, st := range sp.Stock {
+    if st.Count < st.Max {
+      sp.restocking = true
+      act.Add(sp.RestockDelay, sp.restock)
+      return
+    }
+  }
+}
+
+func (sp *Shop) restock() error {
+  sp.restocking = false
+  if ref.Deref(sp.Ref()) != sp {
+    return nil
+  }
+  for _, st := range sp.Stock {
+    if st.Count < st.Max {
+      st.Count++
+    }
+  }
+  sp.Arm()
+  return nil
+}
+
+// Saves the Shop, along with its stock prototypes (which are saved first).
+//
+func (sp Shop) Save(s save.Saver) {
+  stock := make([]interface{}, 0, len(sp.Stock))
+  for _, st := range sp.Stock {
+    st.Proto.Save(s)
+    stock = append(stock, []interface{}{ st.Proto.Ref(), st.Price, st.Count, st.Max, })
+  }
+  s.Encode([]interface{}{ "shop", sp.Ref(), sp.NormalName.ToSaveString(),
+                          sp.NormalName.PrepPhrase, sp.RestockDelay, sp.BuyRate,
+                          sp.serial, stock, })
+}
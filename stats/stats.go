@@ -0,0 +1,265 @@
+// stats.go
+//
+// dta5 character attributes, skills, and experience
+//
+// updated 2026-10-19
+//
+// Which attributes and skills characters have is up to the game world; they
+// are declared in world files:
+//
+//  ["attr",  "tag", "Name", default_value ]
+//  ["skill", "tag", "Name", "attr_tag", default_rank ]
+//
+// Each skill is governed by an attribute (which may be "" for none).
+//
+// A character's Sheet holds the value of each attribute, and the rank of
+// (and experience toward the next rank in) each skill. Experience is
+// gained by Award()ing it, which scripts and verbs do when a character does
+// something worth learning from; every XPPerRank * (rank + 1) points of
+// experience in a skill raises its rank by one.
+//
+// A Sheet also keeps track of a character's health, which runs from zero
+// to MaxHealth; see Heal().
+//
+// Check() makes a skill check: a random number from 0 to 99, plus RankBonus
+// for each rank of the skill, plus AttrBonus for each point the governing
+// attribute is above AttrBase (or minus, for each point below), must meet
+// or beat the difficulty. An untrained character with an average attribute
+// succeeds at a check of difficulty d (100 - d)% of the time.
+//
+package stats
+
+import( "fmt"; "math/rand"; "time";
+        "dta5/log"; "dta5/msg";
+)
+
+func log(lvl dtalog.LogLvl, fmtstr string, args ...interface{}) {
+  dtalog.Log(lvl, fmt.Sprintf("stats: " + fmtstr, args...))
+}
+
+// The "average" attribute value, at which an attribute neither helps nor
+// hinders skill checks.
+//
+const AttrBase int = 10
+
+var XPPerRank int = 100
+var RankBonus int = 5
+var AttrBonus int = 2
+var MaxHealth int = 100
+
+var statRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// A Def declares an attribute or a skill.
+//
+type Def struct {
+  Tag     string
+  Name    string
+  Attr    string  // governing attribute; skills only
+  Default int
+}
+
+// The declared attributes and skills, in the order they were declared
+// (which is the order SCORE displays them).
+//
+var Attrs  []*Def
+var Skills []*Def
+
+// This function prepares the package for loading the game. This should be
+// called both on initial game loading and when loading a saved game state.
+//
+func Initialize() {
+  Attrs  = make([]*Def, 0, 0)
+  Skills = make([]*Def, 0, 0)
+}
+
+func find(defs []*Def, tag string) *Def {
+  for _, d := range defs {
+    if d.Tag == tag {
+      return d
+    }
+  }
+  return nil
+}
+
+// NewAttr() and NewSkill() declare attributes and skills; they're meant to
+// be called by the dta5/load package. Redeclaring a tag replaces it.
+//
+func NewAttr(tag, name string, dflt int) *Def {
+  d := &Def{ Tag: tag, Name: name, Default: dflt, }
+  if old := find(Attrs, tag); old != nil {
+    *old = *d
+    return old
+  }
+  Attrs = append(Attrs, d)
+  return d
+}
+
+func NewSkill(tag, name, attr string, dflt int) *Def {
+  d := &Def{ Tag: tag, Name: name, Attr: attr, Default: dflt, }
+  if old := find(Skills, tag); old != nil {
+    *old = *d
+    return old
+  }
+  Skills = append(Skills, d)
+  return d
+}
+
+type Skill struct {
+  Rank int
+  XP   int
+}
+
+// A Sheet holds one character's attributes and skills. It's exported (and
+// its fields are) so it can be written as JSON in the player file.
+//
+type Sheet struct {
+  Attrs  map[string]int
+  Skills map[string]*Skill
+  XP     int  // total experience ever gained
+  Health int
+}
+
+func NewSheet() *Sheet {
+  return (&Sheet{}).Fix()
+}
+
+// Fix() fills in anything missing from a Sheet (such as a character from
+// before a stat was declared, or from before there were stats at all) with
+// default values, and returns it (or a new one, if s is nil). A Sheet with
+// no health left starts over at full health.
+//
+func (s *Sheet) Fix() *Sheet {
+  if s == nil {
+    s = &Sheet{}
+  }
+  if s.Attrs == nil {
+    s.Attrs = make(map[string]int)
+  }
+  if s.Skills == nil {
+    s.Skills = make(map[string]*Skill)
+  }
+  if s.Health <= 0 {
+    s.Health = MaxHealth
+  }
+  for _, d := range Attrs {
+    if _, ok := s.Attrs[d.Tag]; !ok {
+      s.Attrs[d.Tag] = d.Default
+    }
+  }
+  for _, d := range Skills {
+    if _, ok := s.Skills[d.Tag]; !ok {
+      s.Skills[d.Tag] = &Skill{ Rank: d.Default, }
+    }
+  }
+  return s
+}
+
+// Attr() returns the value of an attribute; undeclared attributes are
+// AttrBase.
+//
+func (s *Sheet) Attr(tag string) int {
+  if v, ok := s.Attrs[tag]; ok {
+    return v
+  }
+  return AttrBase
+}
+
+// Rank() returns the rank of a skill; undeclared skills are rank 0.
+//
+func (s *Sheet) Rank(tag string) int {
+  if sk, ok := s.Skills[tag]; ok {
+    return sk.Rank
+  }
+  return 0
+}
+
+// Bonus() is what's added to the random roll in a check of the given skill.
+//
+func (s *Sheet) Bonus(tag string) int {
+  var b int = s.Rank(tag) * RankBonus
+  if d := find(Skills, tag); d != nil && d.Attr != "" {
+    b += (s.Attr(d.Attr) - AttrBase) * AttrBonus
+  }
+  return b
+}
+
+// Heal() restores up to n points of health (or takes them away, if n is
+// negative), and returns how much it actually changed.
+//
+func (s *Sheet) Heal(n int) int {
+  old := s.Health
+  s.Health += n
+  if s.Health > MaxHealth {
+    s.Health = MaxHealth
+  } else if s.Health < 0 {
+    s.Health = 0
+  }
+  return s.Health - old
+}
+
+// Anything with a Sheet has stats. pc.PlayerChar is the only Holder.
+//
+type Holder interface {
+  msg.Messageable
+  StatSheet() *Sheet
+}
+
+// Check() makes a skill check (see above) and reports whether it succeeded.
+//
+func Check(who Holder, skill string, difficulty int) bool {
+  roll := statRand.Intn(100) + who.StatSheet().Bonus(skill)
+  log(dtalog.DBG, "Check(%q, %d): rolled %d", skill, difficulty, roll)
+  return roll >= difficulty
+}
+
+// Award() gives experience in a skill, raising its rank (and telling the
+// character so) if enough has accumulated.
+//
+func Award(who Holder, skill string, xp int) {
+  if xp <= 0 {
+    return
+  }
+  d := find(Skills, skill)
+  if d == nil {
+    log(dtalog.DBG, "Award(%q, %d): no such skill", skill, xp)
+    return
+  }
+
+  s := who.StatSheet()
+  s.XP += xp
+  sk, ok := s.Skills[skill]
+  if !ok {
+    sk = &Skill{ Rank: d.Default, }
+    s.Skills[skill] = sk
+  }
+  sk.XP += xp
+  for sk.XP >= XPPerRank * (sk.Rank + 1) {
+    sk.XP -= XPPerRank * (sk.Rank + 1)
+    sk.Rank++
+    who.Deliver(msg.New("txt", "Your skill at %s has improved to rank %d.",
+                        d.Name, sk.Rank))
+  }
+}
+
+// Lines() returns lines of text describing the Sheet, for SCORE.
+//
+func (s *Sheet) Lines() []string {
+  var lines = make([]string, 0, len(Attrs) + len(Skills) + 4)
+  lines = append(lines, fmt.Sprintf("%-16s %3d/%d", "Health", s.Health, MaxHealth))
+  for _, d := range Attrs {
+    lines = append(lines, fmt.Sprintf("%-16s %3d", d.Name, s.Attr(d.Tag)))
+  }
+  if len(Skills) > 0 {
+    lines = append(lines, "Skills:")
+    for _, d := range Skills {
+      var sk = s.Skills[d.Tag]
+      if sk == nil {
+        sk = &Skill{}
+      }
+      lines = append(lines, fmt.Sprintf("  %-14s %3d  (%d/%d)", d.Name, sk.Rank,
+                                        sk.XP, XPPerRank * (sk.Rank + 1)))
+    }
+  }
+  lines = append(lines, fmt.Sprintf("Total experience: %d", s.XP))
+  return lines
+}
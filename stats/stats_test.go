@@ -0,0 +1,70 @@
+// stats_test.go
+//
+// Test suite for dta5/stats
+//
+package stats
+
+import( "testing";
+        "dta5/msg";
+)
+
+type holder struct {
+  sheet *Sheet
+  told  int
+}
+
+func (hp *holder) Deliver(m *msg.Message) { hp.told++ }
+func (hp *holder) StatSheet() *Sheet { return hp.sheet }
+
+func TestDefaults(t *testing.T) {
+  Initialize()
+  NewAttr("str", "Strength", 12)
+  NewSkill("climbing", "Climbing", "str", 1)
+
+  old := &Sheet{ Attrs: map[string]int{ "str": 8, }, }
+  for _, tc := range []struct {
+    s     *Sheet
+    str   int
+    climb int
+  }{
+    { nil, 12, 1, },
+    { old, 8, 1, },
+  } {
+    s := tc.s.Fix()
+    if s.Attr("str") != tc.str || s.Rank("climbing") != tc.climb || s.Health != MaxHealth {
+      t.Errorf("Fix(): str %d, climbing %d, health %d; want %d, %d, %d",
+               s.Attr("str"), s.Rank("climbing"), s.Health, tc.str, tc.climb, MaxHealth)
+    }
+  }
+  s := NewSheet()
+  if s.Attr("nonesuch") != AttrBase || s.Rank("nonesuch") != 0 {
+    t.Errorf("undeclared stats: got %d, %d; want %d, 0",
+             s.Attr("nonesuch"), s.Rank("nonesuch"), AttrBase)
+  }
+}
+
+func TestCheckAndAward(t *testing.T) {
+  Initialize()
+  NewAttr("str", "Strength", AttrBase + 2)
+  NewSkill("climbing", "Climbing", "str", 1)
+  who := &holder{ sheet: NewSheet(), }
+
+  bonus := RankBonus + 2 * AttrBonus
+  if b := who.sheet.Bonus("climbing"); b != bonus {
+    t.Fatalf("Bonus(): got %d, want %d", b, bonus)
+  }
+  for n := 0; n < 100; n++ {
+    if !Check(who, "climbing", bonus) {
+      t.Fatalf("Check() at difficulty %d (the bonus) failed", bonus)
+    }
+    if Check(who, "climbing", bonus + 100) {
+      t.Fatalf("Check() at difficulty %d (past any roll) succeeded", bonus + 100)
+    }
+  }
+
+  Award(who, "climbing", XPPerRank * 2 + 10)
+  if r := who.sheet.Rank("climbing"); r != 2 || who.sheet.Skills["climbing"].XP != 10 || who.told != 1 {
+    t.Errorf("Award(): rank %d with %d xp (told %d times), want rank 2 with 10 (told once)",
+             r, who.sheet.Skills["climbing"].XP, who.told)
+  }
+}
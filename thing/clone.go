@@ -0,0 +1,79 @@
+// clone.go
+//
+// dta5 copying Things
+//
+// updated 2026-10-19
+//
+package thing
+
+import( "dta5/log"; "dta5/ref";
+)
+
+// copyThingList() returns an empty ThingList with the same capacity as tl,
+// belonging to r.
+//
+func copyThingList(tl *ThingList, r ref.Interface) *ThingList {
+  return &ThingList{ Things: make([]Thing, 0, 0),
+                     MassLimit: tl.MassLimit, BulkLimit: tl.BulkLimit,
+                     LocVec: LocVec{ Place: r, Side: tl.Side, },
+                     NoMerge: tl.NoMerge, }
+}
+
+// Clone() makes, ref.Register()s, and returns a copy of t with the reference
+// string nref. The copy is nowhere, is empty (if t is a container), and
+// shares t's description. Only the types in this package can be cloned;
+// for anything else (or if nref is already taken), Clone() returns nil.
+//
+func Clone(t Thing, nref string) Thing {
+  if ref.Deref(nref) != nil {
+    log(dtalog.ERR, "Clone(%q, %q): reference already in use", t.Ref(), nref)
+    return nil
+  }
+  
+  var c Thing
+  switch x := t.(type) {
+  case *Item:
+    ni := *x
+    c = &ni
+  case *Clothing:
+    nc := *x
+    c = &nc
+  case *Stack:
+    ns := *x
+    c = &ns
+  case *Consumable:
+    nc := *x
+    c = &nc
+  case *LightSource:
+    nl := *x
+    nl.lit = false
+    c = &nl
+  case *ItemContainer:
+    nic := *x
+    nic.Sides = make(map[byte]*ThingList)
+    for s, tl := range x.Sides {
+      nic.Sides[s] = copyThingList(tl, &nic)
+    }
+    nic.lock = copyLock(x.lock)
+    c = &nic
+  case *WornContainer:
+    nwc := *x
+    nwc.contents = copyThingList(x.contents, &nwc)
+    nwc.lock = copyLock(x.lock)
+    c = &nwc
+  default:
+    return nil
+  }
+  
+  // Every case above is a pointer to something embedding an Item.
+  ip := c.(interface{ item() *Item }).item()
+  ip.ref = nref
+  ip.where = LocVec{ Place: nil, Side: 0, }
+  if ip.descRef == "" {
+    ip.descRef = t.Ref()
+  }
+  ref.Register(c)
+  return c
+}
+
+func (ip *Item) item() *Item { return ip }
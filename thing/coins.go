@@ -0,0 +1,62 @@
+// coins.go
+//
+// dta5 money
+//
+// updated 2026-10-19
+//
+// Money lying around the world (on the ground, in a chest) is a Stack of
+// the kind CoinKind. PlayerChars don't carry these; they keep a count of
+// coins in a purse (see dta5/pc).
+//
+package thing
+
+import( "fmt";
+)
+
+// The kind of the Stacks that are money.
+//
+const CoinKind string = "coin"
+
+// CoinNoun is what a single coin is called.
+//
+var CoinNoun string = "gold coin"
+
+// The mass and bulk of a single coin.
+//
+var CoinMass float32 = 0.01
+var CoinBulk float32 = 0.002
+
+// Anything with a value (in coins) should implement this interface. The
+// Item implements it, so all the types embedding an Item do, too.
+//
+type Valued interface {
+  Value() int
+  SetValue(int)
+}
+
+// CoinStr() returns a phrase describing n coins, like "1 gold coin" or
+// "17 gold coins".
+//
+func CoinStr(n int) string {
+  if n == 1 {
+    return "1 " + CoinNoun
+  }
+  return fmt.Sprintf("%d %ss", n, CoinNoun)
+}
+
+// Creates, ref.Register()s, and returns a new pile of n coins.
+//
+func NewCoins(nref string, n int) *Stack {
+  ns := NewStack(nref, "a " + CoinNoun, "", "", CoinMass, CoinBulk, CoinKind, n)
+  ns.SetValue(1)
+  return ns
+}
+
+// IsCoins() returns t as a *Stack if it's money.
+//
+func IsCoins(t Thing) (*Stack, bool) {
+  if s, ok := t.(*Stack); ok && (s.kind == CoinKind) {
+    return s, true
+  }
+  return nil, false
+}
@@ -0,0 +1,75 @@
+// consumable.go
+//
+// dta5 food, drink, and other things that get used up
+//
+// updated 2026-10-19
+//
+// A Consumable is an Item that can be eaten, drunk, or used some number of
+// times (its portions); each time, its Effects happen to whoever consumed
+// it (see dta5/effect), and when its last portion is gone, so is it.
+//
+package thing
+
+import( "dta5/ref"; "dta5/save";
+)
+
+// The ways a Consumable can be consumed (and the verbs that do it).
+//
+const(  EAT   = "eat"
+        DRINK = "drink"
+        USE   = "use"
+)
+
+// An Effect names something that happens (see dta5/effect for which
+// there are) and its arguments, as read from the world file.
+//
+type Effect struct {
+  Tag  string
+  Args []interface{}
+}
+
+type Consumable struct {
+  Item
+  how      string
+  portions int
+  effects  []Effect
+}
+
+// MakeConsumable() turns a regular Item into a Consumable that's consumed
+// by how (EAT, DRINK, or USE) in the given number of portions.
+//
+func MakeConsumable(ip *Item, how string, portions int, effects []Effect) *Consumable {
+  nc := Consumable{
+    Item:     *ip,
+    how:      how,
+    portions: portions,
+    effects:  effects,
+  }
+  ref.Reregister(&nc)
+  return &nc
+}
+
+func (c Consumable) How() string { return c.how }
+func (c Consumable) Portions() int { return c.portions }
+func (c Consumable) Effects() []Effect { return c.effects }
+
+// Consume() uses up one portion, and returns how many are left.
+//
+func (cp *Consumable) Consume() int {
+  if cp.portions > 0 {
+    cp.portions--
+  }
+  return cp.portions
+}
+
+func (c Consumable) Save(s save.Saver) {
+  effs := make([]interface{}, 0, len(c.effects))
+  for _, e := range c.effects {
+    effs = append(effs, append([]interface{}{ e.Tag, }, e.Args...))
+  }
+  var data []interface{} = []interface{} {
+    "consumable", c.Ref(), c.NormalName.ToSaveString(), c.NormalName.PrepPhrase,
+    false, c.mass.Save(), c.bulk.Save(), c.how, c.portions, effs, }
+  s.Encode(data)
+  c.saveAttrs(s)
+}
@@ -2,7 +2,7 @@
 //
 // dta5 Container interface and basic container implementation
 //
-// updated 2017-08-30
+// updated 2026-10-19
 //
 // A Container is something that can store other Things (generally in
 // some combination of in, on, behind, and under it).
@@ -41,8 +41,13 @@ type ItemContainer struct {
   WillToggle bool
   OpenState bool
   Sides map[byte]*ThingList
+  lock *Lock
 }
 
+// The order in which sides of Containers are saved and listed.
+//
+var sideOrder = []byte{ IN, ON, BEHIND, UNDER, }
+
 // Creates, ref.Register()s, and returns a new *ItemContainer. If you actually
 // want to store stuff in it, you need to use the AddSide() method.
 //
@@ -88,6 +93,18 @@ func (ic *ItemContainer) Side(s byte) *ThingList {
   }
 }
 
+// The mass of an ItemContainer includes the mass of everything in, on,
+// behind, and under it. (Its own mass, without its contents, is what gets
+// saved.)
+//
+func (ic ItemContainer) Mass() TVal {
+  sides := make([]*ThingList, 0, len(ic.Sides))
+  for _, s := range sideOrder {
+    sides = append(sides, ic.Sides[s])
+  }
+  return withContents(ic.mass, sides...)
+}
+
 func (ic ItemContainer) IsToggleable() bool {
   return ic.WillToggle
 }
@@ -119,9 +136,14 @@ func (ic ItemContainer) Save(s save.Saver) {
   }
   cont = append(cont, side_info)
   s.Encode(cont)
+  ic.saveAttrs(s)
+  if ic.lock != nil {
+    ic.lock.Save(s, ic.ref)
+  }
   
-  for sid, tl := range ic.Sides {
-    if len(tl.Things) > 0 {
+  for _, sid := range sideOrder {
+    tl := ic.Sides[sid]
+    if (tl != nil) && (len(tl.Things) > 0) {
       for _, t := range tl.Things {
         t.Save(s)
       }
@@ -0,0 +1,166 @@
+// light.go
+//
+// dta5 light sources
+//
+// updated 2026-10-19
+//
+// Some rooms are dark (see dta5/room); in them, nobody can see anything
+// unless something Luminous is there. A LightSource is an Item that can be
+// lit and put out, and (unless its fuel is unlimited) burns down while it's
+// lit, going out for good when its fuel is gone.
+//
+// Burning is done by the act queue, so a LightSource loaded already lit
+// (from a saved game, or with a player's inventory) doesn't start burning
+// until it's Arm()ed; see ArmLights(), below.
+//
+package thing
+
+import(
+        "dta5/act"; "dta5/log"; "dta5/msg"; "dta5/name"; "dta5/ref";
+        "dta5/save"; "dta5/util";
+)
+
+// How often (in seconds) a lit LightSource's fuel is burned down.
+//
+var BurnInterval float64 = 10.0
+
+// Anything that might give off light.
+//
+type Luminous interface {
+  IsLit() bool
+}
+
+// Anything that can be lit and put out.
+//
+type Lightable interface {
+  Luminous
+  SetLit(bool) bool
+  Fuel() float64
+}
+
+type LightSource struct {
+  Item
+  lit  bool
+  fuel float64  // seconds of light left; negative means unlimited
+  gen  int      // incremented every time it's lit, to stop stale burns
+}
+
+// Creates, ref.Register()s, and returns a new LightSource with the given
+// amount of fuel (in seconds; a negative amount never runs out).
+//
+func NewLightSource(nref, artAdjNoun, prep string, plural bool,
+                    mass, bulk interface{}, fuel float64, lit bool) *LightSource {
+  nip := NewItem(nref, artAdjNoun, prep, plural, mass, bulk)
+  nl := LightSource{
+    Item: *nip,
+    lit:  lit,
+    fuel: fuel,
+  }
+  ref.Reregister(&nl)
+  return &nl
+}
+
+// MakeLightSource() turns a regular Item into a LightSource.
+//
+func MakeLightSource(ip *Item, fuel float64, lit bool) *LightSource {
+  nl := LightSource{
+    Item: *ip,
+    lit:  lit,
+    fuel: fuel,
+  }
+  ref.Reregister(&nl)
+  return &nl
+}
+
+func (l LightSource) IsLit() bool { return l.lit }
+func (l LightSource) Fuel() float64 { return l.fuel }
+
+// SetLit() lights or puts out the LightSource. Lighting one with no fuel
+// left does nothing (and returns false).
+//
+func (lp *LightSource) SetLit(b bool) bool {
+  if b && (lp.fuel == 0) {
+    return false
+  }
+  lp.lit = b
+  if b {
+    lp.Arm()
+  }
+  return true
+}
+
+// Arm() starts a lit LightSource burning down.
+//
+func (lp *LightSource) Arm() {
+  if !lp.lit || (lp.fuel < 0) {
+    return
+  }
+  lp.gen++
+  lp.burn(lp.gen)
+}
+
+func (lp *LightSource) burn(gen int) {
+  act.Add(BurnInterval, func() error {
+    if (gen != lp.gen) || !lp.lit || (ref.Deref(lp.ref) != lp) {
+      return nil
+    }
+    lp.fuel -= BurnInterval
+    if lp.fuel > 0 {
+      lp.burn(gen)
+      return nil
+    }
+    lp.fuel = 0
+    lp.lit = false
+    log(dtalog.DBG, "(*LightSource %q) burn(): burned out", lp.ref)
+    m := msg.New("txt", "%s flickers and goes out.", util.Cap(lp.Normal(name.DEF_ART)))
+    if where := outermost(lp); where != nil {
+      where.Deliver(m)
+    }
+    return nil
+  })
+}
+
+// outermost() returns whatever's at the top of the chain of places t is
+// in (generally a room.Room), if it can be sent messages.
+//
+func outermost(t Thing) msg.Messageable {
+  var p ref.Interface = t.Loc().Place
+  for {
+    pt, ok := p.(Thing)
+    if !ok {
+      break
+    }
+    up := pt.Loc().Place
+    if up == nil {
+      break
+    }
+    p = up
+  }
+  if m, ok := p.(msg.Messageable); ok {
+    return m
+  }
+  return nil
+}
+
+// ArmLights() Arm()s every LightSource that's lit. It should be called
+// after the game is loaded and the act queue has been started.
+//
+func ArmLights() {
+  lights := make([]*LightSource, 0, 0)
+  ref.Walk(func(r ref.Interface) {
+    if lp, ok := r.(*LightSource); ok && lp.lit {
+      lights = append(lights, lp)
+    }
+  })
+  for _, lp := range lights {
+    lp.Arm()
+  }
+}
+
+func (l LightSource) Save(s save.Saver) {
+  var data []interface{} = []interface{} {
+    "light", l.Ref(), l.NormalName.ToSaveString(), l.NormalName.PrepPhrase,
+    false, l.mass.Save(), l.bulk.Save(), l.fuel, l.lit, }
+  s.Encode(data)
+  l.saveAttrs(s)
+}
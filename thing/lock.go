@@ -0,0 +1,111 @@
+// lock.go
+//
+// dta5 locks and keys
+//
+// updated 2026-10-19
+//
+// Anything Lockable may have a Lock, which is either locked or not, is
+// opened by any of a list of keys (by ref), and may be picked with a
+// lockpick (see dta5/pc for the PICK verb), with a chance of failure that
+// depends on its Difficulty.
+//
+// A key is just any Thing whose ref is in a Lock's Keys. A key-ring is
+// just a Container with keys in (or on) it; see KeyFor().
+//
+package thing
+
+import( "dta5/save";
+)
+
+type Lock struct {
+  Locked     bool
+  Keys       []string
+  Difficulty int      // percent chance of a PICK attempt failing
+}
+
+// Things that can have a Lock should implement this interface. Lock()
+// returns nil if the Thing has no Lock.
+//
+type Lockable interface {
+  Lock() *Lock
+  SetLock(*Lock)
+}
+
+// The Difficulty of a Lock made without specifying one.
+//
+var DefaultDifficulty int = 50
+
+// LockpickNoun is what a PlayerChar must be holding to PICK a Lock.
+//
+var LockpickNoun string = "lockpick"
+
+// Fits() returns true if t is one of the Lock's keys.
+//
+func (l Lock) Fits(t Thing) bool {
+  for _, k := range l.Keys {
+    if k == t.Ref() {
+      return true
+    }
+  }
+  return false
+}
+
+// AddKey() adds another key to the Lock.
+//
+func (lp *Lock) AddKey(keyRef string) {
+  for _, k := range lp.Keys {
+    if k == keyRef {
+      return
+    }
+  }
+  lp.Keys = append(lp.Keys, keyRef)
+}
+
+// KeyFor() returns t, if it fits the Lock, or the first key in or on t
+// that does (if t is a key-ring), or nil.
+//
+func (l Lock) KeyFor(t Thing) Thing {
+  if l.Fits(t) {
+    return t
+  }
+  if c, ok := t.(Container); ok {
+    for _, s := range []byte{IN, ON} {
+      if tl := c.Side(s); tl != nil {
+        for _, k := range tl.Things {
+          if l.Fits(k) {
+            return k
+          }
+        }
+      }
+    }
+  }
+  return nil
+}
+
+// copyLock() returns a pointer to a separate copy of the Lock (or nil).
+//
+func copyLock(lp *Lock) *Lock {
+  if lp == nil {
+    return nil
+  }
+  nl := *lp
+  nl.Keys = append([]string{}, lp.Keys...)
+  return &nl
+}
+
+// Save() saves the Lock as belonging to the Thing with reference string r:
+//
+//  ["lock", "ref", locked, difficulty, "key_ref"... ]
+//
+func (l Lock) Save(s save.Saver, r string) {
+  data := []interface{}{ "lock", r, l.Locked, l.Difficulty, }
+  for _, k := range l.Keys {
+    data = append(data, k)
+  }
+  s.Encode(data)
+}
+
+func (ic ItemContainer) Lock() *Lock { return ic.lock }
+func (icp *ItemContainer) SetLock(l *Lock) { icp.lock = l }
+func (w WornContainer) Lock() *Lock { return w.lock }
+func (wp *WornContainer) SetLock(l *Lock) { wp.lock = l }
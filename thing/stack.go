@@ -0,0 +1,118 @@
+// stack.go
+//
+// dta5 countable things
+//
+// updated 2026-10-19
+//
+// A Stack is some number of identical things (arrows, coins, torches)
+// treated as a single Thing with a single ref. Its name, mass, bulk, and
+// value all scale with its count.
+//
+// Stacks of the same Kind merge when they're added to the same ThingList
+// (see ThingList.Add()), and a Stack can be Split() to get some of it.
+//
+package thing
+
+import( "fmt";
+        "dta5/name"; "dta5/ref"; "dta5/save";
+)
+
+type Stack struct {
+  Item
+  plural string
+  kind   string
+  count  int
+}
+
+// Creates, ref.Register()s, and returns a new Stack:
+//   * nref is the Stack's reference string
+//   * artAdjNoun and prep name one of the things (see dta5/name); plural
+//     is the plural noun ("" to have it guessed)
+//   * unitMass and unitBulk are the mass and bulk of one of the things
+//   * kind identifies which Stacks can merge together ("" to use nref)
+//   * count is how many there are
+//
+func NewStack(nref, artAdjNoun, plural, prep string, unitMass, unitBulk interface{},
+              kind string, count int) *Stack {
+  ni := NewItem(nref, artAdjNoun, prep, false, unitMass, unitBulk)
+  if kind == "" {
+    kind = nref
+  }
+  if plural == "" {
+    plural = name.Pluralize(ni.Noun)
+  }
+  ns := Stack{ Item: *ni, plural: plural, kind: kind, count: count, }
+  ref.Reregister(&ns)
+  return &ns
+}
+
+func (s Stack) Count() int { return s.count }
+func (sp *Stack) SetCount(n int) { sp.count = n }
+func (s Stack) Kind() string { return s.kind }
+
+func (s Stack) cname() name.CountName {
+  return name.CountName{ NormalName: s.NormalName, Plural: s.plural, Count: s.count, }
+}
+
+// Stack implements name.Name with a name.CountName.
+//
+func (s Stack) Full(p name.NameParam) string   { return s.cname().Full(p) }
+func (s Stack) Normal(p name.NameParam) string { return s.cname().Normal(p) }
+func (s Stack) Short(p name.NameParam) string  { return s.cname().Short(p) }
+func (s Stack) Match(toks []string) bool       { return s.cname().Match(toks) }
+func (s Stack) SubjPronoun() string   { return s.cname().SubjPronoun() }
+func (s Stack) ObjPronoun() string    { return s.cname().ObjPronoun() }
+func (s Stack) PossPronoun() string   { return s.cname().PossPronoun() }
+func (s Stack) ReflexPronoun() string { return s.cname().ReflexPronoun() }
+
+// The Item's mass, bulk, and value are those of a single thing.
+//
+func (s Stack) Mass() TVal {
+  return TVal{ VT: s.mass.VT, Value: s.mass.Value * float32(s.count), }
+}
+func (s Stack) Bulk() TVal {
+  return TVal{ VT: s.bulk.VT, Value: s.bulk.Value * float32(s.count), }
+}
+func (s Stack) Value() int { return s.value * s.count }
+
+// Split() takes n things off the Stack and returns them as a new Stack (with
+// a new ref), or returns nil if there aren't more than n in the Stack. The
+// new Stack has the same location as the old one, but isn't actually in
+// the ThingList there, so removing it from that ThingList does no harm.
+//
+func (sp *Stack) Split(n int) *Stack {
+  if (n < 1) || (n >= sp.count) {
+    return nil
+  }
+  var nref string
+  for x := 0; ; x++ {
+    nref = fmt.Sprintf("%s.%d", sp.ref, x)
+    if ref.Deref(nref) == nil {
+      break
+    }
+  }
+  ns := *sp
+  ns.ref = nref
+  ns.count = n
+  ref.Register(&ns)
+  sp.count -= n
+  return &ns
+}
+
+// Merge() adds the things in o to the Stack, and deregisters o.
+//
+func (sp *Stack) Merge(o *Stack) {
+  sp.count += o.count
+  o.count = 0
+  o.SetLoc(LocVec{ Place: nil, Side: 0 })
+  ref.Deregister(o)
+}
+
+// ["stack", ref, artAdjNoun, prep, plural, unit_mass, unit_bulk, kind, count]
+//
+func (s Stack) Save(sv save.Saver) {
+  sv.Encode([]interface{}{ "stack", s.ref, s.NormalName.ToSaveString(),
+                           s.NormalName.PrepPhrase, s.plural, s.mass.Save(),
+                           s.bulk.Save(), s.kind, s.count, })
+  s.saveAttrs(sv)
+}
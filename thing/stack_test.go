@@ -0,0 +1,76 @@
+// stack_test.go
+//
+// Test suite for dta5/thing Stacks
+//
+package thing
+
+import( "testing";
+        "dta5/ref";
+)
+
+func TestStackSplit(t *testing.T) {
+  tests := []struct {
+    n      int
+    split  bool
+    left   int
+  }{
+    { 0, false, 10, },
+    { 3, true, 7, },
+    { 9, true, 1, },
+    { 10, false, 10, },
+    { 12, false, 10, },
+  }
+  for _, tc := range tests {
+    s := NewStack("st-arrows", "an arrow", "", "", 0.1, 0.5, "arrow", 10)
+    part := s.Split(tc.n)
+    if (part != nil) != tc.split {
+      t.Errorf("Split(%d): got %v, want split %v", tc.n, part, tc.split)
+      continue
+    }
+    if s.Count() != tc.left {
+      t.Errorf("Split(%d): %d left, want %d", tc.n, s.Count(), tc.left)
+    }
+    if part == nil {
+      continue
+    }
+    if part.Count() != tc.n || part.Ref() == s.Ref() || ref.Deref(part.Ref()) != ref.Interface(part) {
+      t.Errorf("Split(%d): got %d under %q, want %d under a new registered ref",
+               tc.n, part.Count(), part.Ref(), tc.n)
+    }
+    if m := part.Mass().Value + s.Mass().Value; m < 0.99 || m > 1.01 {
+      t.Errorf("Split(%d): masses add up to %v, want 1.0", tc.n, m)
+    }
+
+    s.Merge(part)
+    if s.Count() != 10 || part.Count() != 0 || ref.Deref(part.Ref()) != nil {
+      t.Errorf("Merge() after Split(%d): got %d and %d (registered: %v), want 10 and 0 (false)",
+               tc.n, s.Count(), part.Count(), ref.Deref(part.Ref()) != nil)
+    }
+    ref.Deregister(s)
+  }
+}
+
+func TestStackAdd(t *testing.T) {
+  quiver := NewItem("st-quiver", "a quiver", "", false, 0.5, 2.0)
+  tl := NewThingList(VT_UNLTD, VT_UNLTD, quiver, IN)
+  a := NewStack("st-a", "an arrow", "", "", 0.1, 0.5, "arrow", 4)
+  b := NewStack("st-b", "an arrow", "", "", 0.1, 0.5, "arrow", 6)
+  c := NewStack("st-c", "a bolt", "", "", 0.2, 0.5, "bolt", 2)
+
+  tl.Add(a)
+  if got := tl.Add(b); got != Thing(a) || a.Count() != 10 {
+    t.Errorf("Add() of the same kind: got %v with %d, want the first Stack with 10", got, a.Count())
+  }
+  tl.Add(c)
+  if len(tl.Things) != 2 {
+    t.Errorf("Add() of another kind: got %d Stacks, want 2", len(tl.Things))
+  }
+
+  tl.NoMerge = true
+  d := NewStack("st-d", "an arrow", "", "", 0.1, 0.5, "arrow", 1)
+  tl.Add(d)
+  if len(tl.Things) != 3 || a.Count() != 10 {
+    t.Errorf("Add() to a NoMerge ThingList: got %d Stacks, first with %d; want 3, 10",
+             len(tl.Things), a.Count())
+  }
+}
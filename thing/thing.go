@@ -15,7 +15,7 @@
 // may later get merged with ref.Interface), and has a pointer to where it
 // is (see LocVec, below).
 //
-// updated 2017-08-15
+// updated 2026-10-19
 //
 package thing
 
@@ -131,6 +131,7 @@ type ThingList struct {
   MassLimit TVal
   BulkLimit TVal
   LocVec
+  NoMerge bool  // if true, Stacks added don't merge (see Add(), below)
 }
 
 // NewThingList()
@@ -169,7 +170,9 @@ func NewThingList(mass, bulk interface{}, r ref.Interface, s byte) *ThingList {
                      LocVec: LocVec{ Place: r, Side: s}}
 }
 
-// Return the total amount of mass contained in the ThingList.
+// Return the total amount of mass contained in the ThingList. (Since the
+// Mass() of a Container includes its contents, this counts everything in
+// the ThingList, however deeply it's nested.)
 //
 func (tl ThingList) TotalMass() TVal {
   var tot float32 = 0.0
@@ -201,14 +204,53 @@ func (tl ThingList) TotalBulk() TVal {
   return TVal{ VT: VT_LTD, Value: tot, }
 }
 
+// withContents() returns the mass m of a Container, plus that of its
+// contents on each of the given sides (any of which may be nil). Something
+// too massive to move stays that way, whatever's in it.
+//
+func withContents(m TVal, sides ...*ThingList) TVal {
+  if m.VT == VT_UNLTD {
+    return m
+  }
+  tot := m.Value
+  for _, tl := range sides {
+    if tl == nil {
+      continue
+    }
+    tm := tl.TotalMass()
+    if tm.VT == VT_UNLTD {
+      return INFTY
+    }
+    tot += tm.Value
+  }
+  if (m.VT == VT_NONE) && (tot == 0.0) {
+    return m
+  }
+  return TVal{ VT: VT_LTD, Value: tot, }
+}
+
 // Adds t to the ThingList, updating its location. Checking whether t will
 // fit is the calling function's responsibility.
 //
-func (tl *ThingList) Add(t Thing) {
+// If t is a Stack, and there's already a Stack of the same Kind in the
+// ThingList (and the ThingList isn't NoMerge), t gets merged into that one
+// (and ceases to exist). Add() returns whichever Thing ends up in the
+// ThingList.
+//
+func (tl *ThingList) Add(t Thing) Thing {
   log(dtalog.DBG, "(*ThingList [%q, %d]) Add(%q) called",
                     tl.LocVec.Place.Ref(), tl.LocVec.Side, t.Ref())
+  if s, ok := t.(*Stack); ok && !tl.NoMerge {
+    for _, x := range tl.Things {
+      if xs, xok := x.(*Stack); xok && (xs != s) && (xs.kind == s.kind) {
+        xs.Merge(s)
+        return xs
+      }
+    }
+  }
   tl.Things = append(tl.Things, t)
   t.SetLoc(tl.LocVec)
+  return t
 }
 
 // Removes t from the ThingList, setting its location to be nothing. You
@@ -361,6 +403,9 @@ type Item struct {
   mass TVal
   bulk TVal
   where LocVec
+  value int
+  descRef string
+  hidden int
 }
 
 // Create and ref.Register() a new Item with the given parameters:
@@ -423,12 +468,40 @@ func (i Item) Bulk() TVal { return i.bulk }
 // Item implements desc.Interface
 func (i *Item) SetDescPage(pagep *string) { i.descPage = pagep }
 func (i Item) Desc() string {
+  if i.descRef != "" {
+    if d, ok := ref.Deref(i.descRef).(desc.Interface); ok {
+      return d.Desc()
+    }
+  }
   if i.descPage == nil {
     return "You notice nothing special."
   }
   return desc.GetDesc(*(i.descPage), i.ref)
 }
 
+// SetDescRef() makes the Item share the description of the thing with the
+// given reference string (for example, the prototype it was copied from).
+//
+func (ip *Item) SetDescRef(r string) { ip.descRef = r }
+
+// The value of an Item (in coins) is what a shop would pay for it before
+// taking its cut.
+//
+func (i Item) Value() int { return i.value }
+func (ip *Item) SetValue(v int) { ip.value = v }
+
+// A Concealable thing can be hidden; its concealment is the difficulty of
+// the check (see dta5/stats) it takes to find it by searching. Zero means
+// it isn't hidden at all.
+//
+type Concealable interface {
+  Concealment() int
+  SetConcealment(int)
+}
+
+func (i Item) Concealment() int { return i.hidden }
+func (ip *Item) SetConcealment(n int) { ip.hidden = n }
+
 // Return the Item's location.
 func (i Item) Loc() LocVec { return i.where }
 // Set the Item's location.
@@ -450,4 +523,21 @@ func (i Item) Save(s save.Saver) {
   cont = append(cont, i.mass.Save())
   cont = append(cont, i.bulk.Save())
   s.Encode(cont)
+  i.saveAttrs(s)
+}
+
+// saveAttrs() saves the Item attributes that aren't part of its main
+// "item" (or "itemc", or "cloth", etc.) record. It should be called by the
+// Save() method of every type that embeds an Item, after saving that record.
+//
+func (i Item) saveAttrs(s save.Saver) {
+  if i.value != 0 {
+    s.Encode([]interface{}{ "value", i.ref, i.value, })
+  }
+  if i.descRef != "" {
+    s.Encode([]interface{}{ "descas", i.ref, i.descRef, })
+  }
+  if i.hidden != 0 {
+    s.Encode([]interface{}{ "hide", i.ref, i.hidden, })
+  }
 }
@@ -8,10 +8,11 @@ import( "fmt"; "testing";
 )
 
 func TestThingList(t *testing.T) {
-  tl := NewThingList(VT_UNLTD, VT_UNLTD, nil, 0)
-  i0 := NewItem("i0", "a first thingy", "", false, nil, 1.0, 1.0)
-  i1 := NewItem("i1", "a/an second item", "with a longer description", false, nil, 1.0, 2.0)
-  i2 := NewItem("i2", "a lump", "", false, nil, 2.0, 0.5)
+  box := NewItem("i-box", "a box", "", false, 1.0, 1.0)
+  tl := NewThingList(VT_UNLTD, VT_UNLTD, box, IN)
+  i0 := NewItem("i0", "a first thingy", "", false, 1.0, 1.0)
+  i1 := NewItem("i1", "a/an second item", "with a longer description", false, 1.0, 2.0)
+  i2 := NewItem("i2", "a lump", "", false, 2.0, 0.5)
   
   fmt.Printf("%s\n", tl.EnglishList())
   tl.Add(i0)
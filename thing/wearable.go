@@ -2,7 +2,7 @@
 //
 // wearable dta5 items
 //
-// updated 2017-08-12
+// updated 2026-10-19
 //
 package thing
 
@@ -57,6 +57,7 @@ func (c Clothing) Save(s save.Saver) {
     "cloth", c.Ref(), c.NormalName.ToSaveString(), c.NormalName.PrepPhrase,
     false, c.mass.Save(), c.bulk.Save(), c.slot, }
   s.Encode(data)
+  c.saveAttrs(s)
 }
 
 // The WornContainer implements both the Container and the Wearable
@@ -69,6 +70,7 @@ type WornContainer struct {
   willToggle bool
   openState  bool
   contents   *ThingList
+  lock       *Lock
 }
 
 // Creates, ref.Register()s, and returns a new WornContainer.
@@ -91,6 +93,7 @@ func NewWornContainer(nref, artAdjNoun, prep string, plural bool,
   return &nwc
 }
 
+func (w WornContainer) Mass() TVal { return withContents(w.mass, w.contents) }
 func (w WornContainer) IsToggleable() bool { return w.willToggle }
 func (w WornContainer) IsOpen() bool { return w.openState }
 func (wp *WornContainer) SetToggleable(b bool) { wp.willToggle = b }
@@ -129,6 +132,10 @@ func (w WornContainer) Save(s save.Saver) {
     false, w.mass.Save(), w.bulk.Save(), w.slot, w.willToggle, w.openState,
     w.contents.MassLimit.Save(), w.contents.BulkLimit.Save(), }
   s.Encode(data)
+  w.saveAttrs(s)
+  if w.lock != nil {
+    w.lock.Save(s, w.ref)
+  }
   
   if len(w.contents.Things) > 0 {
     var pop_data []interface{} = []interface{} { "pop", w.Ref(), sideStr(IN), }
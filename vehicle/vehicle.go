@@ -0,0 +1,252 @@
+// vehicle.go
+//
+// dta5 vehicles
+//
+// updated 2026-10-19
+//
+// A Vehicle is an ordinary thing.Thing (a ferry, a cart, a tram) that has
+// an interior room.Room and moves from room to room along a route. It's
+// boarded through a door.Doorway sitting in or on the Thing, bound to a
+// Doorway in the interior Room; since going through a Doorway leads to
+// wherever the other side is, getting off leads to wherever the Vehicle
+// happens to be.
+//
+// The route is a cycle of Stops. The Vehicle stays at each Stop for its
+// Secs and then moves on to the next; its Door is open only while it is at
+// a Stop where passengers Board (so the boarding Doorway shouldn't be
+// toggleable). Each move is announced to the Room it leaves, the Room it
+// arrives in, and its passengers. In world files (see dta5/load):
+//
+//  ["vehicle", "thing_ref", "interior_room_ref", "doorway_ref",
+//              [ "room_ref", secs, board ]... ]
+//
+// The Thing itself is made and placed by the world file like any other; a
+// Vehicle starts from whichever Stop it's in. Which Stop it's at is kept in
+// the Thing's ref.Data (under DataKey), so it's saved with the game.
+//
+package vehicle
+
+import( "fmt";
+        "dta5/act"; "dta5/door"; "dta5/log"; "dta5/msg"; "dta5/name"; "dta5/ref";
+        "dta5/room"; "dta5/thing"; "dta5/util";
+)
+
+func log(lvl dtalog.LogLvl, fmtstr string, args ...interface{}) {
+  dtalog.Log(lvl, fmt.Sprintf("vehicle: " + fmtstr, args...))
+}
+
+// The ref.Data key under which a Vehicle's Thing keeps the index of the
+// Stop it's at.
+//
+const DataKey = "vehicle_stop"
+
+type Stop struct {
+  Room  string
+  Secs  float64
+  Board bool
+}
+
+type Vehicle struct {
+  Ref      string
+  Interior string
+  Doorway  string
+  Route    []Stop
+}
+
+// All loaded Vehicles, by the ref of their Things, so they can be Arm()ed
+// after loading.
+//
+var Vehicles = make(map[string]*Vehicle)
+
+// This function prepares the package for loading the game. This should be
+// called both on initial game loading and when loading a saved game state.
+//
+func Initialize() {
+  Vehicles = make(map[string]*Vehicle)
+}
+
+// New() makes a Vehicle; it's meant to be called by the dta5/load package.
+// It won't go anywhere until it's Arm()ed.
+//
+func New(thingRef, interior, doorway string, route []Stop) *Vehicle {
+  if _, ok := Vehicles[thingRef]; ok {
+    log(dtalog.WRN, "New(%q): replacing existing vehicle", thingRef)
+  }
+  v := &Vehicle{ Ref: thingRef, Interior: interior, Doorway: doorway, Route: route, }
+  Vehicles[thingRef] = v
+  return v
+}
+
+// Is() returns whether the Thing t is a Vehicle.
+//
+func Is(t thing.Thing) bool {
+  _, ok := Vehicles[t.Ref()]
+  return ok
+}
+
+// Of() returns the Vehicle whose interior is rp, or nil.
+//
+func Of(rp *room.Room) *Vehicle {
+  if rp == nil {
+    return nil
+  }
+  for _, v := range Vehicles {
+    if v.Interior == rp.Ref() {
+      return v
+    }
+  }
+  return nil
+}
+
+// Thing() returns the Vehicle's Thing, or nil.
+//
+func (v *Vehicle) Thing() thing.Thing {
+  t, _ := ref.Deref(v.Ref).(thing.Thing)
+  return t
+}
+
+// Inside() returns the Vehicle's interior Room, or nil.
+//
+func (v *Vehicle) Inside() *room.Room {
+  r, _ := ref.Deref(v.Interior).(*room.Room)
+  return r
+}
+
+// At() returns the Room the Vehicle is in, or nil.
+//
+func (v *Vehicle) At() *room.Room {
+  if t := v.Thing(); t != nil {
+    r, _ := t.Loc().Place.(*room.Room)
+    return r
+  }
+  return nil
+}
+
+// stop() returns the index of the Stop the Vehicle is at: the one in its
+// Thing's ref.Data if it's there, otherwise the first one in the Room it's
+// in (or 0).
+//
+func (v *Vehicle) stop() int {
+  t := v.Thing()
+  if t == nil {
+    return 0
+  }
+  if n, ok := ref.GetData(t, DataKey).(float64); ok && int(n) < len(v.Route) {
+    if at := v.At(); (at != nil) && (v.Route[int(n)].Room == at.Ref()) {
+      return int(n)
+    }
+  }
+  if at := v.At(); at != nil {
+    for n, s := range v.Route {
+      if s.Room == at.Ref() {
+        return n
+      }
+    }
+  }
+  return 0
+}
+
+// Doorways() returns the Vehicle's boarding Doorway (the one on the Thing)
+// and the one in its interior, or nils if it doesn't have a bound
+// door.Doorway.
+//
+func (v *Vehicle) Doorways() (*door.Doorway, *door.Doorway) {
+  dwy, ok := ref.Deref(v.Doorway).(*door.Doorway)
+  if !ok || !dwy.IsBound() {
+    return nil, nil
+  }
+  if dwy.Loc().Place == ref.Interface(v.Inside()) {
+    return dwy.Other(), dwy
+  }
+  return dwy, dwy.Other()
+}
+
+// setDoor() opens the Vehicle's Door (or closes it).
+//
+func (v *Vehicle) setDoor(open bool) {
+  if dwy, ok := ref.Deref(v.Doorway).(*door.Doorway); ok && dwy.IsBound() {
+    dwy.SetOpen(open)
+  }
+}
+
+// Arm() opens or closes the Vehicle's Door to suit the Stop it's at and
+// schedules its next move (each move schedules the next).
+//
+func (v *Vehicle) Arm() {
+  if len(v.Route) == 0 {
+    log(dtalog.WRN, "(*Vehicle %q) Arm(): no route", v.Ref)
+    return
+  }
+  s := v.Route[v.stop()]
+  v.setDoor(s.Board)
+  if s.Secs <= 0 {
+    log(dtalog.WRN, "(*Vehicle %q) Arm(): time at %q must be positive", v.Ref, s.Room)
+    return
+  }
+  act.Add(s.Secs, v.tick)
+}
+
+func (v *Vehicle) tick() error {
+  if Vehicles[v.Ref] != v {
+    return nil
+  }
+  t := v.Thing()
+  if t == nil {
+    log(dtalog.WRN, "(*Vehicle %q) tick(): no such thing", v.Ref)
+    return nil
+  }
+  n := (v.stop() + 1) % len(v.Route)
+  if err := v.MoveTo(n); err != nil {
+    log(dtalog.WRN, "(*Vehicle %q) tick(): %s", v.Ref, err)
+  }
+  v.Arm()
+  return nil
+}
+
+// MoveTo() takes the Vehicle to the nth Stop of its route, telling
+// everyone concerned.
+//
+func (v *Vehicle) MoveTo(n int) error {
+  t := v.Thing()
+  dest, ok := ref.Deref(v.Route[n].Room).(*room.Room)
+  if !ok {
+    return fmt.Errorf("stop %q is not a room", v.Route[n].Room)
+  }
+  vname := t.Normal(name.DEF_ART)
+  inside := v.Inside()
+
+  v.setDoor(false)
+  side := room.CONTENTS
+  if from := v.At(); from != nil {
+    if t.Loc().Side == room.SCENERY {
+      side = room.SCENERY
+      from.Scenery.Remove(t)
+    } else {
+      from.Contents.Remove(t)
+    }
+    from.Deliver(msg.New("txt", "%s leaves.", util.Cap(vname)))
+  }
+  if inside != nil {
+    inside.Deliver(msg.New("txt", "%s moves on.", util.Cap(vname)))
+  }
+  if side == room.SCENERY {
+    dest.Scenery.Add(t)
+  } else {
+    dest.Contents.Add(t)
+  }
+  ref.SetData(t, DataKey, float64(n))
+
+  if v.Route[n].Board {
+    v.setDoor(true)
+    dest.Deliver(msg.New("txt", "%s arrives and stops here.", util.Cap(t.Normal(0))))
+    if inside != nil {
+      inside.Deliver(msg.New("txt", "%s stops at %s.", util.Cap(vname), dest.Title))
+    }
+  } else {
+    dest.Deliver(msg.New("txt", "%s arrives.", util.Cap(t.Normal(0))))
+    if inside != nil {
+      inside.Deliver(msg.New("txt", "%s passes through %s.", util.Cap(vname), dest.Title))
+    }
+  }
+  return nil
+}
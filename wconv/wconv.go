@@ -0,0 +1,92 @@
+// wconv.go
+//
+// convert dta5 world files to keyed records
+//
+// updated 2026-10-19
+//
+// Rewrites each world file named on the command line, replacing its
+// positional records ( ["item", "ref", ...] ) with the equivalent keyed ones
+// ( {"type": "item", "ref": ...} ); see dta5/load/keyed.go. Records that are
+// already keyed are left as they are, as are records it can't convert (it
+// says which). Blank lines between records are kept.
+//
+//  wconv [ -n ] world.json...
+//
+// With -n, the converted files are written to stdout instead.
+//
+package main
+
+import( "bytes"; "encoding/json"; "flag"; "fmt"; "io"; "os";
+        "dta5/load";
+)
+
+var dryRun bool
+
+func die(err error, fmtstr string, args ...interface{}) {
+  if err != nil {
+    fmt.Printf(fmtstr, args...)
+    panic(err)
+  }
+}
+
+// convert() writes the records in raw to w, keyed.
+//
+func convert(fname string, raw []byte, w io.Writer) error {
+  dcdr := json.NewDecoder(bytes.NewReader(raw))
+  ncdr := json.NewEncoder(w)
+  ncdr.SetEscapeHTML(false)
+  var prev_end int64 = 0
+
+  for dcdr.More() {
+    var x interface{}
+    if err := dcdr.Decode(&x); err != nil {
+      return err
+    }
+    end := dcdr.InputOffset()
+    gap := bytes.TrimLeft(raw[prev_end:end], " \t\r\n")
+    if (prev_end > 0) && (bytes.Count(raw[prev_end:end - int64(len(gap))], []byte("\n")) > 1) {
+      fmt.Fprintln(w)
+    }
+    prev_end = end
+
+    if lst, ok := x.([]interface{}); ok {
+      rec, err := load.Keyed(lst)
+      if err == nil {
+        // Written directly, because an Encoder would squeeze out the
+        // spaces Record.MarshalJSON() puts in.
+        b, err := rec.MarshalJSON()
+        if err != nil {
+          return err
+        }
+        fmt.Fprintf(w, "%s\n", b)
+        continue
+      }
+      fmt.Fprintf(os.Stderr, "%s: leaving %v as it is: %s\n", fname, lst, err)
+    }
+    if err := ncdr.Encode(x); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func main() {
+  flag.BoolVar(&dryRun, "n", false, "write to stdout instead of rewriting files")
+  flag.Parse()
+
+  for _, fname := range flag.Args() {
+    raw, err := os.ReadFile(fname)
+    die(err, "Unable to read %q: %s\n", fname, err)
+
+    var buff bytes.Buffer
+    err = convert(fname, raw, &buff)
+    die(err, "Error converting %q: %s\n", fname, err)
+
+    if dryRun {
+      os.Stdout.Write(buff.Bytes())
+    } else {
+      err = os.WriteFile(fname, buff.Bytes(), 0644)
+      die(err, "Error writing %q: %s\n", fname, err)
+    }
+  }
+}
@@ -0,0 +1,159 @@
+// zone.go
+//
+// dta5 zones: named groups of rooms
+//
+// updated 2026-10-19
+//
+// Room refs are a flat namespace, but a world is generally made of areas
+// (a park, the tunnels under it) that belong together. A Zone groups Rooms
+// under a name, and holds settings that apply to all of them:
+//
+//   * Outdoor: the zone is out under the sky
+//   * Safe:    nobody may be attacked there
+//   * ResetSecs: if positive, every so often the zone's things are put back
+//       the way the world file has them (see Reset())
+//
+// In world files (see dta5/load), a zone can also have a default mood
+// messenger covering all of its rooms:
+//
+//  ["zone", "tag", "Name", { "outdoor": true, "safe": true, "reset": secs,
+//                            "mood": [ min_secs, max_secs, "message"... ] },
+//   "room_refs"... ]
+//
+// A Room belongs to at most one Zone; its name shows in LOOK headers.
+//
+package zone
+
+import( "fmt";
+        "dta5/act"; "dta5/log"; "dta5/room";
+)
+
+func log(lvl dtalog.LogLvl, fmtstr string, args ...interface{}) {
+  dtalog.Log(lvl, fmt.Sprintf("zone: " + fmtstr, args...))
+}
+
+type Zone struct {
+  Tag       string
+  Name      string
+  Rooms     []string
+  Outdoor   bool
+  Safe      bool
+  ResetSecs float64
+}
+
+// All loaded Zones, by tag.
+//
+var Zones = make(map[string]*Zone)
+
+// The Zone each Room belongs to, by the Room's ref.
+//
+var byRoom = make(map[string]*Zone)
+
+// Resetter does the work of Reset(); it's set by the dta5/load package, which
+// knows what the world file puts where.
+//
+var Resetter func(*Zone)
+
+// This function prepares the package for loading the game. This should be
+// called both on initial game loading and when loading a saved game state.
+//
+func Initialize() {
+  Zones = make(map[string]*Zone)
+  byRoom = make(map[string]*Zone)
+}
+
+// New() makes a Zone (with no Rooms); it's meant to be called by the
+// dta5/load package.
+//
+func New(tag, name string) *Zone {
+  if _, ok := Zones[tag]; ok {
+    log(dtalog.WRN, "New(%q): replacing existing zone", tag)
+  }
+  z := &Zone{ Tag: tag, Name: name, Rooms: []string{}, }
+  Zones[tag] = z
+  return z
+}
+
+// Add() puts the Room with ref r in the Zone (taking it out of any other).
+//
+func (z *Zone) Add(r string) {
+  if old, ok := byRoom[r]; ok && (old != z) {
+    log(dtalog.WRN, "(*Zone %q) Add(%q): moving room from zone %q", z.Tag, r, old.Tag)
+    old.remove(r)
+  }
+  if byRoom[r] != z {
+    z.Rooms = append(z.Rooms, r)
+    byRoom[r] = z
+  }
+}
+
+func (z *Zone) remove(r string) {
+  for n, x := range z.Rooms {
+    if x == r {
+      z.Rooms = append(z.Rooms[:n], z.Rooms[n+1:]...)
+      break
+    }
+  }
+  delete(byRoom, r)
+}
+
+// Has() returns whether the Room with ref r is in the Zone.
+//
+func (z *Zone) Has(r string) bool {
+  return byRoom[r] == z
+}
+
+// Of() returns the Zone rp is in, or nil.
+//
+func Of(rp *room.Room) *Zone {
+  if rp == nil {
+    return nil
+  }
+  return byRoom[rp.Ref()]
+}
+
+// IsOutdoor() and IsSafe() return the settings of the Zone rp is in (false
+// if it's in none).
+//
+func IsOutdoor(rp *room.Room) bool {
+  z := Of(rp)
+  return (z != nil) && z.Outdoor
+}
+
+func IsSafe(rp *room.Room) bool {
+  z := Of(rp)
+  return (z != nil) && z.Safe
+}
+
+// Reset() puts the things the world file puts in the Zone's Rooms back as
+// they were when the game started. Things someone has carried off (or put
+// somewhere outside the Zone) are left alone, as are things that weren't
+// in the Zone to begin with.
+//
+func (z *Zone) Reset() {
+  if Resetter == nil {
+    log(dtalog.WRN, "(*Zone %q) Reset(): no Resetter", z.Tag)
+    return
+  }
+  Resetter(z)
+  log(dtalog.DBG, "(*Zone %q) Reset(): done", z.Tag)
+}
+
+// Arm() schedules the Zone's next Reset() (each one schedules the next), if
+// it resets at all.
+//
+func (z *Zone) Arm() {
+  if z.ResetSecs <= 0 {
+    return
+  }
+  act.Add(z.ResetSecs, z.tick)
+}
+
+func (z *Zone) tick() error {
+  if Zones[z.Tag] != z {
+    return nil
+  }
+  z.Reset()
+  z.Arm()
+  return nil
+}
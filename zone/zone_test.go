@@ -0,0 +1,34 @@
+// zone_test.go
+//
+// Test suite for dta5/zone
+//
+package zone
+
+import( "testing";
+        "dta5/room";
+)
+
+func TestZones(t *testing.T) {
+  Initialize()
+  r0 := room.NewRoom("zt-r0", "A Test Room")
+  r1 := room.NewRoom("zt-r1", "Another Test Room")
+  r2 := room.NewRoom("zt-r2", "A Room Outside Any Zone")
+
+  a := New("a", "Zone A")
+  a.Outdoor = true
+  a.Add(r0.Ref())
+  a.Add(r1.Ref())
+  b := New("b", "Zone B")
+  b.Safe = true
+  b.Add(r1.Ref())
+
+  if Of(r0) != a || Of(r1) != b || Of(r2) != nil {
+    t.Errorf("Of(): got %v, %v, %v; want a, b, nil", Of(r0), Of(r1), Of(r2))
+  }
+  if len(a.Rooms) != 1 || a.Has(r1.Ref()) {
+    t.Errorf("moving a room to zone b left it in zone a: %v", a.Rooms)
+  }
+  if !IsOutdoor(r0) || IsOutdoor(r1) || IsSafe(r0) || !IsSafe(r1) || IsSafe(r2) {
+    t.Errorf("zone settings of rooms are wrong")
+  }
+}